@@ -0,0 +1,210 @@
+package fromage
+
+import (
+	"context"
+	"fmt"
+
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+)
+
+// AsyncState is the state of an Async widget's function
+type AsyncState int
+
+const (
+	AsyncIdle    AsyncState = iota // Not started, or cancelled after leaving the screen
+	AsyncLoading                   // Running on the work queue
+	AsyncDone                      // Finished with a value
+	AsyncFailed                    // Finished with an error
+)
+
+// Async runs a function on the theme's work queue the first time it is laid out and
+// renders a loading, success or error view depending on its state. If the widget stops
+// being laid out while loading, the function's context is cancelled at the start of the
+// next frame, and it starts again when the widget next appears. This relies on the
+// window's frames calling Theme.BeginFrame.
+type Async[T any] struct {
+	// Theme reference
+	theme *Theme
+	// Function producing the value
+	fn func(ctx context.Context) (T, error)
+	// Renderers of the three states
+	loading W
+	success func(gtx C, value T) D
+	failure func(gtx C, err error) D
+	// Current state and result
+	state AsyncState
+	value T
+	err   error
+	// Running task, and a counter that invalidates results of superseded runs
+	task       *Task
+	generation int
+	// laidOut records whether the widget was laid out since the last frame started
+	laidOut bool
+	// Default loading and error views
+	spinner *Spinner
+	retry   *ButtonLayout
+}
+
+// NewAsync creates an Async widget for fn. The default views are a spinner, the value
+// formatted as body text, and the error with a retry button.
+func NewAsync[T any](t *Theme, fn func(ctx context.Context) (T, error)) *Async[T] {
+	a := &Async[T]{theme: t, fn: fn, spinner: t.NewSpinner()}
+	a.retry = t.TextButton("Retry").OnClick(a.Retry)
+	return a
+}
+
+// Loading sets the view shown while the function runs
+func (a *Async[T]) Loading(w W) *Async[T] {
+	a.loading = w
+	return a
+}
+
+// Success sets the view of the function's value
+func (a *Async[T]) Success(fn func(gtx C, value T) D) *Async[T] {
+	a.success = fn
+	return a
+}
+
+// Error sets the view of the function's error. It can offer RetryButton or call Retry.
+func (a *Async[T]) Error(fn func(gtx C, err error) D) *Async[T] {
+	a.failure = fn
+	return a
+}
+
+// State returns the current state
+func (a *Async[T]) State() AsyncState { return a.state }
+
+// Value returns the last result
+func (a *Async[T]) Value() (T, error) { return a.value, a.err }
+
+// RetryButton returns the button of the default error view, which calls Retry
+func (a *Async[T]) RetryButton() *ButtonLayout { return a.retry }
+
+// Retry runs the function again, discarding any result or run in progress
+func (a *Async[T]) Retry() {
+	a.Cancel()
+	a.start()
+}
+
+// Cancel cancels a running function and returns the widget to the idle state, so it
+// starts again on the next layout
+func (a *Async[T]) Cancel() {
+	if a.task != nil {
+		a.task.Cancel()
+		a.task = nil
+	}
+	a.generation++
+	if a.state == AsyncLoading {
+		a.state = AsyncIdle
+	}
+}
+
+// start submits the function to the work queue
+func (a *Async[T]) start() {
+	a.generation++
+	generation := a.generation
+	a.state = AsyncLoading
+	a.task = SubmitFunc(a.theme.WorkQueue(), a.fn, func(value T, err error) {
+		if generation != a.generation {
+			return
+		}
+		a.task = nil
+		a.value, a.err = value, err
+		if err != nil {
+			a.state = AsyncFailed
+		} else {
+			a.state = AsyncDone
+		}
+	})
+	a.theme.watchAsync(a)
+}
+
+// sweep runs at the start of each frame while loading and cancels the function if the
+// widget was not laid out in the previous frame. It reports whether to keep watching.
+func (a *Async[T]) sweep() bool {
+	if a.state != AsyncLoading {
+		return false
+	}
+	if !a.laidOut {
+		a.Cancel()
+		return false
+	}
+	a.laidOut = false
+	return true
+}
+
+// Layout starts the function if needed and renders the view for the current state
+func (a *Async[T]) Layout(gtx C) D {
+	a.laidOut = true
+	if a.state == AsyncIdle {
+		a.start()
+	}
+	state := a.state
+	var d D
+	switch a.state {
+	case AsyncDone:
+		if a.success != nil {
+			d = a.success(gtx, a.value)
+		} else {
+			d = a.theme.Body1(fmt.Sprint(a.value)).Layout(gtx)
+		}
+	case AsyncFailed:
+		if a.failure != nil {
+			d = a.failure(gtx, a.err)
+		} else {
+			d = a.errorView(gtx)
+		}
+	default:
+		if a.loading != nil {
+			d = a.loading(gtx)
+		} else {
+			d = a.spinner.Layout(gtx)
+		}
+	}
+	// A retry clicked during this layout shows the loading view on the next frame
+	if a.state != state {
+		gtx.Execute(op.InvalidateCmd{})
+	}
+	return d
+}
+
+// errorView shows the error message above the retry button
+func (a *Async[T]) errorView(gtx C) D {
+	t := a.theme
+	return t.VFlex().
+		Rigid(t.Body2(a.err.Error()).Color(t.Colors.Error()).Layout).
+		Rigid(func(gtx C) D {
+			return layout.Inset{Top: t.TextSize / 2}.Layout(gtx, a.retry.Layout)
+		}).
+		Layout(gtx)
+}
+
+// Theme async methods
+
+// asyncWatcher is a loading Async widget checked at the start of each frame
+type asyncWatcher interface {
+	sweep() bool
+}
+
+// watchAsync registers a loading Async widget for the sweep at frame start
+func (t *Theme) watchAsync(w asyncWatcher) {
+	for _, existing := range t.asyncs {
+		if existing == w {
+			return
+		}
+	}
+	t.asyncs = append(t.asyncs, w)
+}
+
+// sweepAsync cancels loading Async widgets that were not laid out in the last frame
+func (t *Theme) sweepAsync() {
+	kept := t.asyncs[:0]
+	for _, w := range t.asyncs {
+		if w.sweep() {
+			kept = append(kept, w)
+		}
+	}
+	clear(t.asyncs[len(kept):])
+	t.asyncs = kept
+}
@@ -0,0 +1,120 @@
+package fromage
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/font/gofont"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+)
+
+// newAsyncTestTheme creates a theme whose work queue signals ready on every result
+func newAsyncTestTheme(t *testing.T) (*Theme, chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	th := NewThemeWithMode(ctx, NewColors,
+		text.NewShaper(text.NoSystemFonts(), text.WithCollection(gofont.Collection())),
+		unit.Dp(16), ThemeModeLight)
+	ready := make(chan struct{}, 8)
+	th.invalidate.Store(func() { ready <- struct{}{} })
+	return th, ready
+}
+
+// awaitFrame waits for a result and drains it as BeginFrame would
+func awaitFrame(t *testing.T, th *Theme, ready <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the job")
+	}
+	th.drainWork()
+	th.sweepAsync()
+}
+
+func asyncTestContext() C {
+	return layout.Context{Ops: new(op.Ops), Constraints: layout.Exact(image.Pt(200, 100))}
+}
+
+func TestAsyncSuccess(t *testing.T) {
+	th, ready := newAsyncTestTheme(t)
+	var rendered int
+	a := NewAsync(th, func(context.Context) (int, error) { return 7, nil }).
+		Success(func(gtx C, v int) D { rendered = v; return D{} })
+	if a.State() != AsyncIdle {
+		t.Fatal("Expected the function not to run before layout")
+	}
+	a.Layout(asyncTestContext())
+	if a.State() != AsyncLoading {
+		t.Fatalf("Expected loading after the first layout, got %v", a.State())
+	}
+	awaitFrame(t, th, ready)
+	a.Layout(asyncTestContext())
+	if a.State() != AsyncDone || rendered != 7 {
+		t.Errorf("Expected the value to be rendered, got state %v and %d", a.State(), rendered)
+	}
+}
+
+func TestAsyncErrorAndRetry(t *testing.T) {
+	th, ready := newAsyncTestTheme(t)
+	fail := errors.New("offline")
+	calls := 0
+	a := NewAsync(th, func(context.Context) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", fail
+		}
+		return "online", nil
+	})
+	a.Layout(asyncTestContext())
+	awaitFrame(t, th, ready)
+	if _, err := a.Value(); a.State() != AsyncFailed || !errors.Is(err, fail) {
+		t.Fatalf("Expected the error state, got %v and %v", a.State(), err)
+	}
+	a.Retry()
+	if a.State() != AsyncLoading {
+		t.Fatalf("Expected retry to start loading, got %v", a.State())
+	}
+	a.Layout(asyncTestContext())
+	awaitFrame(t, th, ready)
+	if v, err := a.Value(); a.State() != AsyncDone || v != "online" || err != nil {
+		t.Errorf("Expected the retried value, got %v, %q and %v", a.State(), v, err)
+	}
+}
+
+func TestAsyncCancelsWhenHidden(t *testing.T) {
+	th, _ := newAsyncTestTheme(t)
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	a := NewAsync(th, func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return 0, ctx.Err()
+	})
+	a.Layout(asyncTestContext())
+	<-started
+	// Laid out in the last frame, so it keeps running
+	th.sweepAsync()
+	if a.State() != AsyncLoading {
+		t.Fatalf("Expected the function to keep running, got %v", a.State())
+	}
+	// Not laid out in the last frame
+	th.sweepAsync()
+	if a.State() != AsyncIdle {
+		t.Errorf("Expected the widget to return to idle, got %v", a.State())
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the function's context to be cancelled")
+	}
+	if len(th.asyncs) != 0 {
+		t.Errorf("Expected no watched widgets, got %d", len(th.asyncs))
+	}
+}
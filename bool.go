@@ -28,6 +28,8 @@ type Bool struct {
 	changed bool
 	// Callback function for value changes
 	onChange BoolHook
+	// Observable value the switch shows and edits
+	bound binding[bool]
 	// Visual styling
 	background   color.NRGBA // Background color of the switch track
 	foreground   color.NRGBA // Color of the thumb circle
@@ -93,6 +95,13 @@ func (b *Bool) Value(value bool) *Bool {
 	return b
 }
 
+// Bind makes the switch show and edit an observable value
+func (b *Bool) Bind(v *Value[bool]) *Bool {
+	b.bound.bind(v)
+	v.Invalidates(b.theme)
+	return b
+}
+
 // GetValue returns the current boolean value
 func (b *Bool) GetValue() bool {
 	return b.value
@@ -173,10 +182,17 @@ func (b *Bool) Pressed() bool {
 
 // Layout renders the Material Design switch
 func (b *Bool) Layout(g C) D {
+	// Follow changes to the bound value
+	if v, ok := b.bound.pull(); ok && v != b.value {
+		b.value = v
+		b.startAnimation(g.Now)
+	}
+
 	// Handle click events BEFORE layout
 	if b.clickable.Clicked(g) {
 		b.value = !b.value
 		b.changed = true
+		b.bound.push(b.value)
 		if b.onChange != nil {
 			b.onChange(b.value)
 		}
@@ -304,10 +320,10 @@ func (b *Bool) updateAnimation(g C) {
 		return
 	}
 
-	const animationDuration = 250 * time.Millisecond
-	elapsed := g.Now.Sub(b.animationStart)
+	motion := b.theme.motion()
+	progress, done := motion.Progress(b.animationStart, g.Now, motion.Medium, motion.Standard)
 
-	if elapsed >= animationDuration {
+	if done {
 		// Animation complete
 		if b.value {
 			b.animationProgress = 1.0
@@ -318,12 +334,6 @@ func (b *Bool) updateAnimation(g C) {
 		return
 	}
 
-	// Calculate progress (0.0 to 1.0)
-	progress := float32(elapsed) / float32(animationDuration)
-
-	// Apply easing function (ease-out for smooth deceleration)
-	progress = 1.0 - (1.0-progress)*(1.0-progress)
-
 	if b.value {
 		// Animating to ON state (0.0 -> 1.0)
 		b.animationProgress = progress
@@ -342,21 +352,15 @@ func (b *Bool) updateColorTransition(g C) {
 		return
 	}
 
-	const colorTransitionDuration = 250 * time.Millisecond
-	elapsed := g.Now.Sub(b.colorTransitionStart)
+	motion := b.theme.motion()
+	progress, done := motion.Progress(b.colorTransitionStart, g.Now, motion.Medium, motion.Standard)
 
-	if elapsed >= colorTransitionDuration {
+	if done {
 		// Color transition complete
 		b.isColorTransitioning = false
 		return
 	}
 
-	// Calculate progress (0.0 to 1.0)
-	progress := float32(elapsed) / float32(colorTransitionDuration)
-
-	// Apply easing function (ease-out for smooth deceleration)
-	progress = 1.0 - (1.0-progress)*(1.0-progress)
-
 	// Interpolate colors
 	b.background = b.interpolateColor(b.oldBackground, b.background, progress)
 	b.foreground = b.interpolateColor(b.oldForeground, b.foreground, progress)
@@ -381,8 +385,9 @@ func (b *Bool) UpdateThemeColors(now time.Time) {
 	b.oldBackground = b.background
 	b.oldForeground = b.foreground
 
-	// Update to new theme colors
-	b.background = b.theme.Colors.OnBackground() // Update to new text color
+	// Update to the new theme's text color, the target of any cross-fade that is still
+	// showing the old theme
+	b.background = b.theme.Colors.TargetRoles().OnBackground
 
 	// Update thumb color based on new theme
 	if b.theme.IsLight() {
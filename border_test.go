@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"gio.mleku.dev/font/gofont"
+	"gio.mleku.dev/layout"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 )
@@ -158,3 +159,13 @@ func TestBorderLayout(t *testing.T) {
 		t.Error("Border widget not set for layout")
 	}
 }
+
+func TestBorderGolden(t *testing.T) {
+	th := newGoldenTheme()
+	border := th.BorderPrimary().CornerRadius(8).Width(2).Widget(func(g C) D {
+		return th.Body1("Bordered").Layout(g)
+	})
+	checkGolden(t, "border", th, image.Pt(160, 48), func(g C) D {
+		return layout.UniformInset(8).Layout(g, border.Layout)
+	})
+}
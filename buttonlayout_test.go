@@ -2,6 +2,7 @@ package fromage
 
 import (
 	"context"
+	"image"
 	"image/color"
 	"testing"
 
@@ -161,3 +162,13 @@ func TestButtonLayoutIconButton(t *testing.T) {
 		t.Errorf("IconButton should have corner radius %v", expectedIconRadius)
 	}
 }
+
+func TestButtonGolden(t *testing.T) {
+	th := newGoldenTheme()
+	enabled, disabled := th.TextButton("Save"), th.TextButton("Save").Disabled(true)
+	checkGolden(t, "buttons", th, image.Pt(200, 48), func(g C) D {
+		// Buttons fill a minimum height, so leave the flex free to center them
+		g.Constraints.Min.Y = 0
+		return th.HFlex().SpaceEvenly().AlignMiddle().Rigid(enabled.Layout).Rigid(disabled.Layout).Layout(g)
+	})
+}
@@ -132,7 +132,7 @@ func (t *Theme) CardWithTitle(title string, content W) *Card {
 			SpaceStart().
 			Rigid(func(gtx C) D {
 				// Title label
-				label := t.NewLabel().Text(title).Color(t.Colors.OnSurface()).TextScale(1.2) // Slightly larger for title
+				label := t.heading(6, 1.2, title).Color(t.Colors.OnSurface()) // Slightly larger for title
 				return label.Layout(gtx)
 			}).
 			Rigid(func(gtx C) D {
@@ -153,7 +153,7 @@ func (t *Theme) CardWithTitleAndColor(title string, backgroundColor color.NRGBA,
 			SpaceStart().
 			Rigid(func(gtx C) D {
 				// Title label
-				label := t.NewLabel().Text(title).Color(t.Colors.OnSurface()).TextScale(1.2) // Slightly larger for title
+				label := t.heading(6, 1.2, title).Color(t.Colors.OnSurface()) // Slightly larger for title
 				return label.Layout(gtx)
 			}).
 			Rigid(func(gtx C) D {
@@ -1,6 +1,7 @@
 package fromage
 
 import (
+	"image"
 	"image/color"
 	"testing"
 
@@ -143,3 +144,9 @@ func TestCardConfiguration(t *testing.T) {
 		t.Error("All corners not set correctly")
 	}
 }
+
+func TestCardGolden(t *testing.T) {
+	th := newGoldenTheme()
+	card := th.CardWithTitle("Title", th.Body2("Card content").Layout)
+	checkGolden(t, "card", th, image.Pt(200, 120), card.Layout)
+}
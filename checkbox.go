@@ -27,6 +27,8 @@ type Checkbox struct {
 	changed bool
 	// Callback function for value changes
 	onChange CheckboxHook
+	// Observable value the checkbox shows and edits
+	bound binding[bool]
 	// Visual styling
 	label           string
 	labelColor      color.NRGBA
@@ -59,8 +61,8 @@ func (t *Theme) NewCheckbox(value bool) *Checkbox {
 		borderColor:     t.Colors.OnBackground(), // Use theme text color
 		cornerRadius:    unit.Dp(2),
 		size:            unit.Dp(20),
-		textSize:        unit.Sp(float32(t.TextSize) * 14.0 / 16.0),
-		font:            font.Font{},
+		textSize:        t.typography().BodyMedium.Size(t.TextSize),
+		font:            t.typography().BodyMedium.Font,
 		checkedIcon:     nil, // Will use default checkmark
 		uncheckedIcon:   nil, // Will use default empty box
 	}
@@ -75,6 +77,13 @@ func (c *Checkbox) Value(value bool) *Checkbox {
 	return c
 }
 
+// Bind makes the checkbox show and edit an observable value
+func (c *Checkbox) Bind(v *Value[bool]) *Checkbox {
+	c.bound.bind(v)
+	v.Invalidates(c.theme)
+	return c
+}
+
 // GetValue returns the current checkbox value
 func (c *Checkbox) GetValue() bool {
 	return c.value
@@ -164,10 +173,16 @@ func (c *Checkbox) Pressed() bool {
 
 // Layout renders the checkbox widget
 func (c *Checkbox) Layout(g C) D {
+	// Follow changes to the bound value
+	if v, ok := c.bound.pull(); ok {
+		c.value = v
+	}
+
 	// Handle click events BEFORE layout
 	if c.clickable.Clicked(g) {
 		c.value = !c.value
 		c.changed = true
+		c.bound.push(c.value)
 		if c.onChange != nil {
 			c.onChange(c.value)
 		}
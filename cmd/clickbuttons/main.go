@@ -16,7 +16,6 @@ import (
 	"gio.mleku.dev/unit"
 	"gio.mleku.dev/widget"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 	"lol.mleku.dev/log"
 )
 
@@ -56,7 +55,7 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 
 	// Initialize application state
 	appState = &AppState{
@@ -81,27 +80,9 @@ func main() {
 		app.Size(unit.Dp(1200), unit.Dp(1200)),
 		app.Title("Right-Click Popup Demo"),
 	)
-	w.Run(loop(w.Window, th))
-}
-
-func loop(w *app.Window, th *fromage.Theme) func() {
-	return func() {
-		var ops op.Ops
-		// Create a fromage window wrapper
-		fromageWindow := &fromage.Window{Window: w, Theme: th}
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				mainUI(gtx, th, fromageWindow)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th, w)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme, w *fromage.Window) {
@@ -9,13 +9,11 @@ import (
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/op/clip"
 	"gio.mleku.dev/op/paint"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 	"lol.mleku.dev/log"
 )
 
@@ -59,30 +57,14 @@ func main() {
 	currentSurfaceTint := th.Colors.GetSurfaceTint()
 	appState.colorSelector.SetColor(currentSurfaceTint)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Size(
 		unit.Dp(800), unit.Dp(600)),
 		app.Title("Color Selector Demo"),
 	)
-	w.Run(loop(w.Window, th, w))
-}
-
-func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				mainUI(gtx, th)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme) {
@@ -7,12 +7,10 @@ import (
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/op/paint"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 )
 
 // Import aliases from fromage package
@@ -31,30 +29,14 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Size(
 		unit.Dp(600), unit.Dp(600)),
 		app.Title("Direction Layout Showcase"),
 	)
-	w.Run(loop(w.Window, th, w))
-}
-
-func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				mainUI(gtx, th, window)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th, w)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme, window *fromage.Window) {
@@ -3,12 +3,11 @@ package main
 import (
 	"context"
 	"log"
-	"os"
 	"time"
 
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
-	"gio.mleku.dev/op"
+	"gio.mleku.dev/layout"
 	"gio.mleku.dev/op/paint"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
@@ -25,29 +24,12 @@ func main() {
 		fromage.ThemeModeLight,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Title("Drawer Demo"))
 	w.Option(app.Size(unit.Dp(800), unit.Dp(600)))
-	w.Run(loop(w.Window, th))
-}
-
-func loop(w *app.Window, th *fromage.Theme) func() {
-	return func() {
-		var ops op.Ops
-		// Create a fromage window wrapper
-		fromageWindow := &fromage.Window{Window: w, Theme: th}
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				os.Exit(0)
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				drawerDemo(gtx, th, fromageWindow)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		drawerDemo(gtx, th, w)
+	})
 }
 
 // Import aliases from fromage package
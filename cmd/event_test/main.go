@@ -9,7 +9,6 @@ import (
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/io/pointer"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/op/paint"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
@@ -45,7 +44,7 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 
 	// Initialize test state
 	testState = &TestState{
@@ -66,23 +65,9 @@ func main() {
 		app.Title("EventHandler Test - Simultaneous Events"),
 	)
 
-	w.Run(loop(w.Window, th, w))
-}
-
-func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				mainUI(gtx, th, window)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th, w)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme, w *fromage.Window) {
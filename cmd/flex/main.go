@@ -6,12 +6,10 @@ import (
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/op/paint"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 )
 
 // Import aliases from fromage package
@@ -29,30 +27,14 @@ func main() {
 		unit.Dp(16),
 		fromage.ThemeModeDark,
 	)
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Size(
 		unit.Dp(640), unit.Dp(1280)),
 		app.Title("Flex Demo"),
 	)
-	w.Run(loop(w.Window, th))
-}
-
-func loop(w *app.Window, th *fromage.Theme) func() {
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				mainUI(gtx, th)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme) {
@@ -7,11 +7,9 @@ import (
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 )
 
 // Import aliases from fromage package
@@ -30,38 +28,25 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Size(
 		unit.Dp(400), unit.Dp(300)),
 		app.Title("Float Slider Test"),
 	)
-	w.Run(loop(w.Window, th, w))
+	w.RunFrames(frame(th, w))
 }
 
-func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
+func frame(th *fromage.Theme, window *fromage.Window) func(gtx layout.Context) {
 	slider := th.NewFloat().SetRange(0, 100).SetValue(50)
 	var currentValue float32 = 50
 
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-
-				// Update current value if slider changed
-				if slider.Changed() {
-					currentValue = slider.Value()
-				}
-
-				mainUI(gtx, th, window, slider, currentValue)
-				e.Frame(gtx.Ops)
-			}
+	return func(gtx layout.Context) {
+		// Update current value if slider changed
+		if slider.Changed() {
+			currentValue = slider.Value()
 		}
+
+		mainUI(gtx, th, window, slider, currentValue)
 	}
 }
 
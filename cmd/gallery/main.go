@@ -0,0 +1,282 @@
+// Command gallery shows every fromage widget in one window. Pick a widget from the
+// navigation list to see it live, tweak its settings in the property panel, switch the
+// theme mode and surface tint, and copy the Go code that configures the widget the same
+// way.
+package main
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"strings"
+
+	"gio.mleku.dev/app"
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/font/gofont"
+	"gio.mleku.dev/io/clipboard"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+	"github.com/mleku/fromage"
+	"lol.mleku.dev/chk"
+)
+
+// Import aliases from fromage package
+type (
+	C = fromage.C
+	D = fromage.D
+	W = fromage.W
+)
+
+// themeProps are the theme settings in the property panel
+type themeProps struct {
+	// Index of the fromage.ThemeMode
+	Mode        int         `form:"label=Theme mode,options=Light|Dark|System|High contrast light|High contrast dark"`
+	SurfaceTint color.NRGBA `form:"label=Surface tint"`
+}
+
+// themeModeNames are the Go names of the theme modes, in the order of themeProps.Mode
+var themeModeNames = []string{
+	"fromage.ThemeModeLight",
+	"fromage.ThemeModeDark",
+	"fromage.ThemeModeAuto",
+	"fromage.ThemeModeHighContrastLight",
+	"fromage.ThemeModeHighContrastDark",
+}
+
+// gallery holds the state of the window
+type gallery struct {
+	theme   *fromage.Theme
+	stories []*story
+	// Index of the story shown
+	current int
+	// One navigation button per story
+	nav     []*fromage.ButtonLayout
+	navList layout.List
+	// Property panel of the current story and of the theme
+	panelList  layout.List
+	themeProps themeProps
+	themeForm  *fromage.StructForm
+	// Surface tint the theme started with, left out of snippets until it changes
+	initialTint color.NRGBA
+	// Snippet panel
+	snippetList layout.List
+	copy        *fromage.ButtonLayout
+	// Snippet last copied to the clipboard, and whether a copy was requested this frame
+	copied     string
+	copyQueued bool
+}
+
+func main() {
+	th := fromage.NewThemeWithMode(
+		context.Background(),
+		fromage.NewColors,
+		text.NewShaper(text.WithCollection(gofont.Collection())),
+		unit.Dp(16),
+		fromage.ThemeModeLight,
+	)
+	w := fromage.NewWindow(th).AllowScreenshots()
+	w.Option(app.Size(unit.Dp(1400), unit.Dp(900)), app.Title("Fromage Gallery"))
+
+	g, err := newGallery(w)
+	if chk.E(err) {
+		os.Exit(1)
+	}
+	w.RunFrames(g.Layout)
+}
+
+// newGallery creates the stories and the panels
+func newGallery(w *fromage.Window) (*gallery, error) {
+	th := w.Theme
+	g := &gallery{
+		theme:       th,
+		navList:     layout.List{Axis: layout.Vertical},
+		panelList:   layout.List{Axis: layout.Vertical},
+		snippetList: layout.List{Axis: layout.Vertical},
+		themeProps:  themeProps{Mode: int(th.ThemeMode()), SurfaceTint: th.Colors.GetSurfaceTint()},
+	}
+	g.initialTint = g.themeProps.SurfaceTint
+	var err error
+	if g.stories, err = newStories(w); err != nil {
+		return nil, err
+	}
+	for i, s := range g.stories {
+		g.nav = append(g.nav, th.NewButtonLayout().
+			CornerRadius(0.5).
+			OnClick(func() { g.current = i }).
+			Widget(func(gtx C) D {
+				c := th.Colors.OnSurface()
+				if g.current == i {
+					c = th.Colors.OnSecondaryContainer()
+				}
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				return layout.W.Layout(gtx, func(gtx C) D {
+					return layout.Inset{Left: unit.Dp(12)}.Layout(gtx, th.Body1(s.name).Color(c).Layout)
+				})
+			}))
+	}
+	g.themeForm, err = w.NewStructForm(&g.themeProps)
+	if err != nil {
+		return nil, err
+	}
+	g.themeForm.OnChange(func(field string) {
+		switch field {
+		case "Mode":
+			th.SetThemeMode(fromage.ThemeMode(g.themeProps.Mode))
+		case "SurfaceTint":
+			th.Colors.SetSurfaceTint(g.themeProps.SurfaceTint)
+		}
+	})
+	g.copy = th.NewButtonLayout().
+		CornerRadius(0.5).
+		OnClick(func() { g.copyQueued = true })
+	return g, nil
+}
+
+// Layout draws the navigation list, the preview with its snippet, and the property panel
+func (g *gallery) Layout(gtx C) {
+	th := g.theme
+	paint.Fill(gtx.Ops, th.Colors.Background())
+	th.HFlex().
+		Rigid(g.layoutNav).
+		Flexed(1, func(gtx C) D {
+			return th.VFlex().
+				Flexed(0.6, g.layoutPreview).
+				Flexed(0.4, g.layoutSnippet).
+				Layout(gtx)
+		}).
+		Rigid(g.layoutPanel).
+		Layout(gtx)
+}
+
+// layoutNav draws the list of stories, highlighting the current one
+func (g *gallery) layoutNav(gtx C) D {
+	th := g.theme
+	gtx.Constraints.Min.X = gtx.Dp(220)
+	gtx.Constraints.Max.X = gtx.Constraints.Min.X
+	return th.FillSurface(func(gtx C) D {
+		gtx.Constraints.Min = gtx.Constraints.Max
+		return layout.UniformInset(unit.Dp(8)).Layout(gtx, func(gtx C) D {
+			return g.navList.Layout(gtx, len(g.nav), func(gtx C, i int) D {
+				bg := th.Colors.Surface()
+				if g.current == i {
+					bg = th.Colors.SecondaryContainer()
+				}
+				// The button takes its size from the minimum constraints when they are set
+				gtx.Constraints.Min = image.Pt(gtx.Constraints.Max.X, gtx.Dp(40))
+				return g.nav[i].Background(bg).Layout(gtx)
+			})
+		})
+	}).Layout(gtx)
+}
+
+// layoutPreview draws the current story's widget centered in a frame
+func (g *gallery) layoutPreview(gtx C) D {
+	th := g.theme
+	s := g.stories[g.current]
+	return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx C) D {
+		return th.VFlex().
+			Rigid(func(gtx C) D {
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, th.H5(s.name).Color(th.Colors.OnBackground()).Layout)
+			}).
+			Flexed(1, func(gtx C) D {
+				gtx.Constraints.Min = gtx.Constraints.Max
+				return th.NewBorder().
+					Color(th.Colors.OutlineVariant()).
+					CornerRadius(unit.Dp(12)).
+					Widget(func(gtx C) D {
+						return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx C) D {
+							gtx.Constraints.Min = gtx.Constraints.Max
+							return layout.Center.Layout(gtx, s.preview)
+						})
+					}).
+					Layout(gtx)
+			}).
+			Layout(gtx)
+	})
+}
+
+// layoutSnippet draws the Go code of the current configuration with a copy button
+func (g *gallery) layoutSnippet(gtx C) D {
+	th := g.theme
+	code := g.snippet()
+	if g.copyQueued {
+		g.copyQueued = false
+		g.copied = code
+		gtx.Execute(clipboard.WriteCmd{Type: "application/text", Data: io.NopCloser(strings.NewReader(code))})
+	}
+	label := "Copy"
+	if g.copied == code {
+		label = "Copied"
+	}
+	g.copy.Background(th.Colors.Primary()).Widget(th.Body2(label).Color(th.Colors.OnPrimary()).Layout)
+	lines := strings.Split(code, "\n")
+	return layout.Inset{Left: unit.Dp(16), Right: unit.Dp(16), Bottom: unit.Dp(16)}.Layout(gtx, func(gtx C) D {
+		return th.NewCardWithColor(th.Colors.SurfaceVariant(), func(gtx C) D {
+			gtx.Constraints.Min = gtx.Constraints.Max
+			return th.VFlex().
+				Rigid(func(gtx C) D {
+					return th.HFlex().AlignMiddle().
+						Flexed(1, th.Body2("Go").Color(th.Colors.OnSurfaceVariant()).Layout).
+						Rigid(g.copy.Layout).
+						Layout(gtx)
+				}).
+				Flexed(1, func(gtx C) D {
+					return g.snippetList.Layout(gtx, len(lines), func(gtx C, i int) D {
+						return th.Body2(lines[i]).
+							Font(font.Font{Typeface: "Go Mono, monospace"}).
+							Color(th.Colors.OnSurfaceVariant()).
+							Layout(gtx)
+					})
+				}).
+				Layout(gtx)
+		}).CornerRadius(12).Padding(unit.Dp(12)).Layout(gtx)
+	})
+}
+
+// layoutPanel draws the current story's properties above the theme settings
+func (g *gallery) layoutPanel(gtx C) D {
+	th := g.theme
+	s := g.stories[g.current]
+	gtx.Constraints.Min.X = gtx.Dp(320)
+	gtx.Constraints.Max.X = gtx.Constraints.Min.X
+	sections := []W{
+		th.H6("Properties").Color(th.Colors.OnSurface()).Layout,
+		func(gtx C) D {
+			if s.form == nil {
+				return th.Body2("This widget has no settings").Color(th.Colors.OnSurfaceVariant()).Layout(gtx)
+			}
+			return s.form.Layout(gtx)
+		},
+		th.H6("Theme").Color(th.Colors.OnSurface()).Layout,
+		g.themeForm.Layout,
+	}
+	return th.FillSurface(func(gtx C) D {
+		gtx.Constraints.Min = gtx.Constraints.Max
+		return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx C) D {
+			return g.panelList.Layout(gtx, len(sections), func(gtx C, i int) D {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, sections[i])
+			})
+		})
+	}).Layout(gtx)
+}
+
+// snippet returns the current story's code, after the theme settings that differ from
+// the defaults
+func (g *gallery) snippet() string {
+	var lines []string
+	if g.themeProps.Mode != int(fromage.ThemeModeLight) {
+		lines = append(lines, "th.SetThemeMode("+themeModeNames[g.themeProps.Mode]+")")
+	}
+	if g.themeProps.SurfaceTint != g.initialTint {
+		lines = append(lines, "th.Colors.SetSurfaceTint("+colorLiteral(g.themeProps.SurfaceTint)+")")
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return strings.Join(append(lines, g.stories[g.current].snippet()), "\n")
+}
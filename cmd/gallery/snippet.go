@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/mleku/fromage"
+)
+
+// chain formats a constructor followed by its chained setters, one per line as gofmt
+// leaves them. Empty setters are left out, so optional ones can be passed
+// conditionally.
+func chain(constructor string, setters ...string) string {
+	var b strings.Builder
+	b.WriteString(constructor)
+	for _, s := range setters {
+		if s != "" {
+			b.WriteString(".\n\t")
+			b.WriteString(s)
+		}
+	}
+	return b.String()
+}
+
+// when returns s if ok, for optional setters of chain
+func when(ok bool, s string) string {
+	if ok {
+		return s
+	}
+	return ""
+}
+
+// call formats a method call with its arguments
+func call(name string, args ...string) string {
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// num formats a float as Go source with at most two decimals
+func num(f float32) string {
+	s := strconv.FormatFloat(float64(f), 'f', 2, 32)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// dp formats a size in dp
+func dp(n int) string {
+	return "unit.Dp(" + strconv.Itoa(n) + ")"
+}
+
+// quote formats a string literal
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+// rawString formats a multi-line string literal, splicing in the backquotes a raw
+// string cannot hold
+func rawString(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "` + \"`\" + `") + "`"
+}
+
+// colorLiteral formats a color as a color.NRGBA literal
+func colorLiteral(c color.NRGBA) string {
+	return fmt.Sprintf("color.NRGBA{R: 0x%02x, G: 0x%02x, B: 0x%02x, A: 0x%02x}", c.R, c.G, c.B, c.A)
+}
+
+// role returns the theme color of a role, named as the method of fromage.Colors
+func role(th *fromage.Theme, name string) color.NRGBA {
+	c := th.Colors
+	switch name {
+	case "Primary":
+		return c.Primary()
+	case "OnPrimary":
+		return c.OnPrimary()
+	case "Secondary":
+		return c.Secondary()
+	case "OnSecondary":
+		return c.OnSecondary()
+	case "Tertiary":
+		return c.Tertiary()
+	case "OnTertiary":
+		return c.OnTertiary()
+	case "Error":
+		return c.Error()
+	case "OnError":
+		return c.OnError()
+	case "PrimaryContainer":
+		return c.PrimaryContainer()
+	case "OnPrimaryContainer":
+		return c.OnPrimaryContainer()
+	case "SecondaryContainer":
+		return c.SecondaryContainer()
+	case "OnSecondaryContainer":
+		return c.OnSecondaryContainer()
+	case "SurfaceVariant":
+		return c.SurfaceVariant()
+	case "OnSurfaceVariant":
+		return c.OnSurfaceVariant()
+	case "Surface":
+		return c.Surface()
+	case "OnSurface":
+		return c.OnSurface()
+	case "Outline":
+		return c.Outline()
+	case "OutlineVariant":
+		return c.OutlineVariant()
+	}
+	return c.Primary()
+}
+
+// roleCode formats the theme color of a role as Go source
+func roleCode(name string) string {
+	return "th.Colors." + name + "()"
+}
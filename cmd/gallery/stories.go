@@ -0,0 +1,1046 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+	"gio.mleku.dev/widget"
+	"github.com/mleku/fromage"
+	"golang.org/x/exp/shiny/materialdesign/icons"
+)
+
+// story is a page of the gallery showing one widget
+type story struct {
+	// Name in the navigation list
+	name string
+	// Property panel editing the story's settings, nil if the widget has none
+	form *fromage.StructForm
+	// preview lays out the widget with the current settings
+	preview W
+	// snippet returns Go code configuring the widget the same way
+	snippet func() string
+}
+
+// newStories creates a story for every widget
+func newStories(w *fromage.Window) ([]*story, error) {
+	makers := []func(w *fromage.Window) (*story, error){
+		buttonStory, cardStory, borderStory, fillStory, labelStory, richTextStory,
+		markdownStory, switchStory, checkboxStory, radioStory, intStory, floatStory,
+		scrollbarStory, colorSelectorStory, iconStory, spinnerStory, flexStory,
+		drawerStory, modalStory, globalMenuStory, formStory, structFormStory, asyncStory,
+	}
+	stories := make([]*story, 0, len(makers))
+	for _, create := range makers {
+		s, err := create(w)
+		if err != nil {
+			return nil, err
+		}
+		stories = append(stories, s)
+	}
+	return stories, nil
+}
+
+// newStory creates a story with a property panel for props, a pointer to a struct with
+// form tags, or none if props is nil
+func newStory(w *fromage.Window, name string, props any, preview W, snippet func() string) (*story, error) {
+	s := &story{name: name, preview: preview, snippet: snippet}
+	if props != nil {
+		var err error
+		if s.form, err = w.NewStructForm(props); err != nil {
+			return nil, fmt.Errorf("%s story: %w", name, err)
+		}
+	}
+	return s, nil
+}
+
+// padded lays out a label with the padding buttons give their content
+func padded(label *fromage.Label) W {
+	return func(gtx C) D {
+		return layout.Inset{Top: 10, Bottom: 10, Left: 24, Right: 24}.Layout(gtx, label.Layout)
+	}
+}
+
+// alignments maps alignment options to text alignments and their Go names
+var alignments = map[string]struct {
+	value text.Alignment
+	code  string
+}{
+	"Start":  {text.Start, "text.Start"},
+	"Middle": {text.Middle, "text.Middle"},
+	"End":    {text.End, "text.End"},
+}
+
+// typeStyle returns the theme's type style of a name from the type scale
+func typeStyle(th *fromage.Theme, name string) fromage.TypeStyle {
+	ty := th.Typography
+	switch name {
+	case "DisplaySmall":
+		return ty.DisplaySmall
+	case "HeadlineMedium":
+		return ty.HeadlineMedium
+	case "TitleLarge":
+		return ty.TitleLarge
+	case "BodyLarge":
+		return ty.BodyLarge
+	case "LabelLarge":
+		return ty.LabelLarge
+	}
+	return ty.BodyMedium
+}
+
+type buttonProps struct {
+	Color        string  `form:"options=Primary|Secondary|Tertiary|Error|SurfaceVariant"`
+	CornerRadius float32 `form:"min=0,max=2"`
+	Pill         bool    `form:"label=Pill shape,widget=switch"`
+	Disabled     bool    `form:"widget=switch"`
+	NoInk        bool    `form:"label=Disable inking,widget=switch"`
+}
+
+func buttonStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &buttonProps{Color: "Primary", CornerRadius: 0.25}
+	clicks := 0
+	button := th.NewButtonLayout().OnClick(func() { clicks++ })
+	configure := func() {
+		button.Background(role(th, p.Color)).
+			CornerRadius(p.CornerRadius).
+			Disabled(p.Disabled).
+			DisableInking(p.NoInk).
+			Widget(padded(th.Body1("Button").Color(role(th, "On"+p.Color))))
+		if p.Pill {
+			button.PillRadius()
+		}
+	}
+	preview := func(gtx C) D {
+		configure()
+		return th.VFlex().AlignMiddle().
+			Rigid(button.Layout).
+			Rigid(func(gtx C) D {
+				return layout.Inset{Top: 12}.Layout(gtx,
+					th.Caption(fmt.Sprintf("Clicked %d times", clicks)).Color(th.Colors.OnSurfaceVariant()).Layout)
+			}).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewButtonLayout()",
+			call("Background", roleCode(p.Color)),
+			when(p.Pill, "PillRadius()"),
+			when(!p.Pill, call("CornerRadius", num(p.CornerRadius))),
+			when(p.Disabled, "Disabled(true)"),
+			when(p.NoInk, "DisableInking(true)"),
+			"OnClick(func() {})",
+			call("Widget", `th.Body1("Button").Color(`+roleCode("On"+p.Color)+`).Layout`),
+		)
+	}
+	return newStory(w, "Button", p, preview, snippet)
+}
+
+type cardProps struct {
+	Color        string `form:"options=SurfaceVariant|PrimaryContainer|SecondaryContainer|Primary|Tertiary"`
+	Padding      int    `form:"min=0,max=48"`
+	CornerRadius int    `form:"min=0,max=48"`
+	AllCorners   bool   `form:"label=Round all corners,widget=switch"`
+}
+
+func cardStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &cardProps{Color: "SurfaceVariant", Padding: 16, CornerRadius: 8, AllCorners: true}
+	content := func(gtx C) D {
+		on := role(th, "On"+p.Color)
+		return th.VFlex().
+			Rigid(th.H6("Card title").Color(on).Layout).
+			Rigid(th.Body2("Cards group related content on a colored surface.").Color(on).Layout).
+			Layout(gtx)
+	}
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(360))
+		return th.NewCardWithColor(role(th, p.Color), content).
+			Padding(unit.Dp(p.Padding)).
+			CornerRadius(float32(p.CornerRadius)).
+			AllCorners(p.AllCorners).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain(call("th.NewCardWithColor", roleCode(p.Color), "content"),
+			call("Padding", dp(p.Padding)),
+			call("CornerRadius", strconv.Itoa(p.CornerRadius)),
+			call("AllCorners", strconv.FormatBool(p.AllCorners)),
+		)
+	}
+	return newStory(w, "Card", p, preview, snippet)
+}
+
+type borderProps struct {
+	Color        string `form:"options=Outline|OutlineVariant|Primary|Secondary|Error"`
+	Width        int    `form:"min=1,max=8"`
+	CornerRadius int    `form:"min=0,max=32"`
+}
+
+func borderStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &borderProps{Color: "Outline", Width: 1, CornerRadius: 8}
+	preview := func(gtx C) D {
+		return th.NewBorder().
+			Color(role(th, p.Color)).
+			Width(unit.Dp(p.Width)).
+			CornerRadius(unit.Dp(p.CornerRadius)).
+			Widget(padded(th.Body1("Bordered content").Color(th.Colors.OnSurface()))).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewBorder()",
+			call("Color", roleCode(p.Color)),
+			call("Width", dp(p.Width)),
+			call("CornerRadius", dp(p.CornerRadius)),
+			"Widget(content)",
+		)
+	}
+	return newStory(w, "Border", p, preview, snippet)
+}
+
+type fillProps struct {
+	Color        string `form:"options=Primary|Secondary|Tertiary|Error|SurfaceVariant"`
+	CornerRadius int    `form:"min=0,max=48"`
+	TopLeft      bool   `form:"label=Round top left,widget=switch"`
+	TopRight     bool   `form:"label=Round top right,widget=switch"`
+	BottomLeft   bool   `form:"label=Round bottom left,widget=switch"`
+	BottomRight  bool   `form:"label=Round bottom right,widget=switch"`
+}
+
+// corners returns the corner mask of the props and its Go source
+func (p *fillProps) corners() (int, string) {
+	var mask int
+	var names []string
+	for _, c := range []struct {
+		on   bool
+		bit  int
+		name string
+	}{
+		{p.TopLeft, fromage.CornerNW, "fromage.CornerNW"},
+		{p.TopRight, fromage.CornerNE, "fromage.CornerNE"},
+		{p.BottomLeft, fromage.CornerSW, "fromage.CornerSW"},
+		{p.BottomRight, fromage.CornerSE, "fromage.CornerSE"},
+	} {
+		if c.on {
+			mask |= c.bit
+			names = append(names, c.name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		return 0, "0"
+	case 4:
+		return mask, "fromage.CornerAll"
+	}
+	return mask, strings.Join(names, "|")
+}
+
+func fillStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &fillProps{Color: "Primary", CornerRadius: 16, TopLeft: true, TopRight: true, BottomLeft: true, BottomRight: true}
+	preview := func(gtx C) D {
+		mask, _ := p.corners()
+		content := func(gtx C) D {
+			return layout.UniformInset(32).Layout(gtx, th.Body1("Filled").Color(role(th, "On"+p.Color)).Layout)
+		}
+		return th.NewFill(role(th, p.Color), content).
+			CornerRadius(float32(p.CornerRadius)).
+			Corners(mask).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		_, corners := p.corners()
+		return chain(call("th.NewFill", roleCode(p.Color), "content"),
+			call("CornerRadius", strconv.Itoa(p.CornerRadius)),
+			call("Corners", corners),
+		)
+	}
+	return newStory(w, "Fill", p, preview, snippet)
+}
+
+// labelText is the sample text of the label story
+const labelText = "The quick brown fox jumps over the lazy dog. Pack my box with five dozen liquor jugs."
+
+type labelProps struct {
+	Style      string `form:"options=DisplaySmall|HeadlineMedium|TitleLarge|BodyLarge|BodyMedium|LabelLarge"`
+	Color      string `form:"options=OnSurface|OnSurfaceVariant|Primary|Secondary|Error"`
+	Alignment  string `form:"options=Start|Middle|End"`
+	MaxLines   int    `form:"label=Maximum lines,min=0,max=5"`
+	Selectable bool   `form:"widget=switch"`
+}
+
+func labelStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &labelProps{Style: "BodyLarge", Color: "OnSurface", Alignment: "Start"}
+	// The label persists so its selection survives between frames
+	label := th.NewLabel().Text(labelText)
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(480))
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return label.
+			Style(typeStyle(th, p.Style)).
+			Color(role(th, p.Color)).
+			Alignment(alignments[p.Alignment].value).
+			MaxLines(p.MaxLines).
+			Selectable(p.Selectable).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain(call("th.Styled", "th.Typography."+p.Style, quote(labelText)),
+			call("Color", roleCode(p.Color)),
+			when(p.Alignment != "Start", call("Alignment", alignments[p.Alignment].code)),
+			when(p.MaxLines > 0, call("MaxLines", strconv.Itoa(p.MaxLines))),
+			when(p.Selectable, "Selectable(true)"),
+		)
+	}
+	return newStory(w, "Label", p, preview, snippet)
+}
+
+type richTextProps struct {
+	Style     string `form:"options=TitleLarge|BodyLarge|BodyMedium|LabelLarge"`
+	Alignment string `form:"options=Start|Middle|End"`
+}
+
+func richTextStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &richTextProps{Style: "BodyLarge", Alignment: "Start"}
+	clicked := "none yet"
+	rich := th.NewRichText().
+		Plain("Rich text mixes ").
+		Bold("bold").
+		Plain(", ").
+		Italic("italic").
+		Plain(" and ").
+		Code("code").
+		Plain(" spans with ").
+		Link("links", "https://gioui.org").
+		Plain(" in one paragraph.").
+		OnLink(func(link string) { clicked = link })
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(480))
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return th.VFlex().
+			Rigid(rich.Style(typeStyle(th, p.Style)).Alignment(alignments[p.Alignment].value).Layout).
+			Rigid(func(gtx C) D {
+				return layout.Inset{Top: 12}.Layout(gtx,
+					th.Caption("Last link clicked: "+clicked).Color(th.Colors.OnSurfaceVariant()).Layout)
+			}).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewRichText()",
+			`Plain("Rich text mixes ")`,
+			`Bold("bold")`,
+			`Plain(", ")`,
+			`Italic("italic")`,
+			`Plain(" and ")`,
+			`Code("code")`,
+			`Plain(" spans with ")`,
+			`Link("links", "https://gioui.org")`,
+			`Plain(" in one paragraph.")`,
+			"OnLink(func(link string) {})",
+			call("Style", "th.Typography."+p.Style),
+			when(p.Alignment != "Start", call("Alignment", alignments[p.Alignment].code)),
+		)
+	}
+	return newStory(w, "Rich text", p, preview, snippet)
+}
+
+// markdownDocuments are the sample documents of the markdown story, by option
+var markdownDocuments = []string{
+	"# Heading\n\nA paragraph with **bold**, *italic* and `code`.\n\n## Subheading\n\nMore text under a smaller heading.",
+	"Shopping list:\n\n- Cheese\n- Bread\n- Grapes\n\nSteps:\n\n1. Slice\n2. Arrange\n3. Serve",
+	"Code blocks keep their spacing:\n\n```\nfunc main() {\n\tfmt.Println(\"fromage\")\n}\n```\n\n> Quotes stand apart from the text.",
+}
+
+type markdownProps struct {
+	Document int `form:"options=Headings|Lists|Code and quotes"`
+}
+
+func markdownStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &markdownProps{}
+	shown := 0
+	md := th.NewMarkdown(markdownDocuments[shown])
+	preview := func(gtx C) D {
+		// Source parses the document again, so only set it when it changes
+		if p.Document != shown {
+			shown = p.Document
+			md.Source(markdownDocuments[shown])
+		}
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(480))
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return md.Layout(gtx)
+	}
+	snippet := func() string {
+		return call("th.NewMarkdown", rawString(markdownDocuments[p.Document]))
+	}
+	return newStory(w, "Markdown", p, preview, snippet)
+}
+
+type switchProps struct {
+	Track        string `form:"label=Track color,options=Primary|Secondary|Tertiary|Error|OnSurface"`
+	Width        int    `form:"min=24,max=96"`
+	Height       int    `form:"min=12,max=48"`
+	ThumbSize    int    `form:"min=8,max=44"`
+	CornerRadius int    `form:"min=0,max=24"`
+}
+
+func switchStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &switchProps{Track: "Primary", Width: 36, Height: 20, ThumbSize: 16, CornerRadius: 10}
+	sw := th.Switch(true)
+	preview := func(gtx C) D {
+		return sw.
+			Background(role(th, p.Track)).
+			Foreground(th.Colors.Surface()).
+			Width(unit.Dp(p.Width)).
+			Height(unit.Dp(p.Height)).
+			ThumbSize(unit.Dp(p.ThumbSize)).
+			CornerRadius(unit.Dp(p.CornerRadius)).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.Switch(true)",
+			call("Background", roleCode(p.Track)),
+			call("Foreground", roleCode("Surface")),
+			call("Width", dp(p.Width)),
+			call("Height", dp(p.Height)),
+			call("ThumbSize", dp(p.ThumbSize)),
+			call("CornerRadius", dp(p.CornerRadius)),
+		)
+	}
+	return newStory(w, "Switch", p, preview, snippet)
+}
+
+type checkboxProps struct {
+	Color        string `form:"options=OnSurface|Primary|Secondary|Tertiary|Error"`
+	Size         int    `form:"min=12,max=40"`
+	CornerRadius int    `form:"min=0,max=20"`
+	TextSize     int    `form:"min=10,max=32"`
+}
+
+func checkboxStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &checkboxProps{Color: "Primary", Size: 20, CornerRadius: 2, TextSize: 14}
+	cb := th.NewCheckbox(true).Label("Send me updates")
+	preview := func(gtx C) D {
+		c := role(th, p.Color)
+		return cb.
+			IconColor(c).
+			BorderColor(c).
+			LabelColor(th.Colors.OnSurface()).
+			BackgroundColor(th.Colors.Surface()).
+			Size(unit.Dp(p.Size)).
+			CornerRadius(unit.Dp(p.CornerRadius)).
+			TextSize(unit.Sp(p.TextSize)).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewCheckbox(true)",
+			`Label("Send me updates")`,
+			call("IconColor", roleCode(p.Color)),
+			call("BorderColor", roleCode(p.Color)),
+			call("LabelColor", roleCode("OnSurface")),
+			call("BackgroundColor", roleCode("Surface")),
+			call("Size", dp(p.Size)),
+			call("CornerRadius", dp(p.CornerRadius)),
+			call("TextSize", "unit.Sp("+strconv.Itoa(p.TextSize)+")"),
+		)
+	}
+	return newStory(w, "Checkbox", p, preview, snippet)
+}
+
+type radioProps struct {
+	Layout  int `form:"options=Vertical|Horizontal"`
+	Spacing int `form:"min=0,max=32"`
+}
+
+func radioStory(w *fromage.Window) (*story, error) {
+	p := &radioProps{Spacing: 8}
+	options := []string{"Brie", "Camembert", "Roquefort"}
+	group := w.NewRadioButtonGroup()
+	for i, option := range options {
+		group.AddButton(option, i == 0)
+	}
+	preview := func(gtx C) D {
+		return group.
+			SetLayout(fromage.LayoutDirection(p.Layout)).
+			SetSpacing(unit.Dp(p.Spacing)).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		setters := []string{
+			when(p.Layout == int(fromage.LayoutHorizontal), "SetLayout(fromage.LayoutHorizontal)"),
+			call("SetSpacing", dp(p.Spacing)),
+		}
+		for i, option := range options {
+			setters = append(setters, call("AddButton", quote(option), strconv.FormatBool(i == 0)))
+		}
+		return chain("w.NewRadioButtonGroup()", append(setters, "SetOnChange(func(index int, label string) {})")...)
+	}
+	return newStory(w, "Radio buttons", p, preview, snippet)
+}
+
+// sliderPreview lays out a slider at a fixed width under its value
+func sliderPreview(th *fromage.Theme, value string, slider W) W {
+	return func(gtx C) D {
+		return th.VFlex().AlignMiddle().
+			Rigid(th.H6(value).Color(th.Colors.OnSurface()).Layout).
+			Rigid(func(gtx C) D {
+				gtx.Constraints.Min.X = min(gtx.Dp(320), gtx.Constraints.Max.X)
+				gtx.Constraints.Max.X = gtx.Constraints.Min.X
+				return slider(gtx)
+			}).
+			Layout(gtx)
+	}
+}
+
+type intProps struct {
+	Minimum int `form:"min=-100,max=100"`
+	Maximum int `form:"min=-100,max=200"`
+}
+
+func intStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &intProps{Minimum: 0, Maximum: 100}
+	slider := th.NewInt().SetRange(p.Minimum, p.Maximum).SetValue(50)
+	preview := func(gtx C) D {
+		if p.Minimum < p.Maximum {
+			slider.SetRange(p.Minimum, p.Maximum)
+		}
+		return sliderPreview(th, strconv.Itoa(slider.Value()), func(gtx C) D {
+			return slider.Layout(gtx, th)
+		})(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewInt()",
+			call("SetRange", strconv.Itoa(p.Minimum), strconv.Itoa(p.Maximum)),
+			call("SetValue", strconv.Itoa(slider.Value())),
+			"SetHook(func(value int) {})",
+		)
+	}
+	return newStory(w, "Int slider", p, preview, snippet)
+}
+
+type floatProps struct {
+	Minimum float32 `form:"min=-10,max=10"`
+	Maximum float32 `form:"min=-10,max=20"`
+}
+
+func floatStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &floatProps{Minimum: 0, Maximum: 1}
+	slider := th.NewFloat().SetRange(p.Minimum, p.Maximum).SetValue(0.5)
+	preview := func(gtx C) D {
+		if p.Minimum < p.Maximum {
+			slider.SetRange(p.Minimum, p.Maximum)
+		}
+		return sliderPreview(th, num(slider.Value()), func(gtx C) D {
+			return slider.Layout(gtx, th)
+		})(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewFloat()",
+			call("SetRange", num(p.Minimum), num(p.Maximum)),
+			call("SetValue", num(slider.Value())),
+			"SetHook(func(value float32) {})",
+		)
+	}
+	return newStory(w, "Float slider", p, preview, snippet)
+}
+
+type scrollbarProps struct {
+	Orientation int     `form:"options=Horizontal|Vertical"`
+	Viewport    float32 `form:"label=Visible proportion,min=0.05,max=1"`
+	Width       int     `form:"min=4,max=24"`
+}
+
+func scrollbarStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &scrollbarProps{Viewport: 0.3, Width: 8}
+	bars := []*fromage.Scrollbar{th.NewScrollbar(fromage.Horizontal), th.NewScrollbar(fromage.Vertical)}
+	preview := func(gtx C) D {
+		bar := bars[p.Orientation].SetViewport(p.Viewport).SetWidth(unit.Dp(p.Width))
+		length := gtx.Dp(320)
+		if fromage.Orientation(p.Orientation) == fromage.Horizontal {
+			gtx.Constraints.Min.X = min(length, gtx.Constraints.Max.X)
+		} else {
+			gtx.Constraints.Min.Y = min(length, gtx.Constraints.Max.Y)
+		}
+		return bar.Layout(gtx, th)
+	}
+	snippet := func() string {
+		orientation := "fromage.Horizontal"
+		if fromage.Orientation(p.Orientation) == fromage.Vertical {
+			orientation = "fromage.Vertical"
+		}
+		return chain(call("th.NewScrollbar", orientation),
+			call("SetViewport", num(p.Viewport)),
+			call("SetWidth", dp(p.Width)),
+			"SetHook(func(position float32) {})",
+		)
+	}
+	return newStory(w, "Scrollbar", p, preview, snippet)
+}
+
+func colorSelectorStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	selector := th.NewColorSelector().SetColor(th.Colors.Primary())
+	preview := func(gtx C) D {
+		return th.VFlex().AlignMiddle().
+			Rigid(func(gtx C) D {
+				gtx.Constraints.Min.X = min(gtx.Dp(320), gtx.Constraints.Max.X)
+				gtx.Constraints.Max.X = gtx.Constraints.Min.X
+				return selector.Layout(gtx, th)
+			}).
+			Rigid(th.Body1(fromage.ColorToHex(selector.GetColor())).Color(th.Colors.OnSurface()).Layout).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewColorSelector()",
+			call("SetColor", colorLiteral(selector.GetColor())),
+			"SetOnChange(func(c color.NRGBA) {})",
+		)
+	}
+	return newStory(w, "Color selector", nil, preview, snippet)
+}
+
+// galleryIcons are the icons of the icon story, by option
+var galleryIcons = []struct {
+	data *[]byte
+	code string
+}{
+	{&icons.ActionFavorite, "&icons.ActionFavorite"},
+	{&icons.ActionGrade, "&icons.ActionGrade"},
+	{&icons.ActionHome, "&icons.ActionHome"},
+	{&icons.ActionSettings, "&icons.ActionSettings"},
+}
+
+type iconProps struct {
+	Icon  int    `form:"options=Favorite|Grade|Home|Settings"`
+	Color string `form:"options=OnSurface|Primary|Secondary|Tertiary|Error"`
+	Size  int    `form:"min=12,max=128"`
+}
+
+func iconStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &iconProps{Color: "Primary", Size: 48}
+	preview := func(gtx C) D {
+		return th.NewIcon().
+			Src(galleryIcons[p.Icon].data).
+			Color(role(th, p.Color)).
+			Size(unit.Dp(p.Size)).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewIcon()",
+			call("Src", galleryIcons[p.Icon].code),
+			call("Color", roleCode(p.Color)),
+			call("Size", dp(p.Size)),
+		)
+	}
+	return newStory(w, "Icon", p, preview, snippet)
+}
+
+type spinnerProps struct {
+	Color string `form:"options=Primary|Secondary|Tertiary|Error|OnSurface"`
+	Size  int    `form:"min=16,max=128"`
+	Width int    `form:"label=Stroke width,min=1,max=16"`
+}
+
+func spinnerStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &spinnerProps{Color: "Primary", Size: 48, Width: 4}
+	spinner := th.NewSpinner()
+	preview := func(gtx C) D {
+		return spinner.
+			Color(role(th, p.Color)).
+			Size(unit.Dp(p.Size)).
+			Width(unit.Dp(p.Width)).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return chain("th.NewSpinner()",
+			call("Color", roleCode(p.Color)),
+			call("Size", dp(p.Size)),
+			call("Width", dp(p.Width)),
+		)
+	}
+	return newStory(w, "Spinner", p, preview, snippet)
+}
+
+type flexProps struct {
+	Axis      int    `form:"options=Horizontal|Vertical"`
+	Spacing   string `form:"options=Start|End|Sides|Around|Between|Evenly"`
+	Alignment string `form:"options=Start|Middle|End"`
+	Children  int    `form:"min=1,max=6"`
+}
+
+// flexBoxColors are the fill roles of the flex story's children, in turn
+var flexBoxColors = []string{"Primary", "Secondary", "Tertiary"}
+
+func flexStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &flexProps{Spacing: "Evenly", Alignment: "Middle", Children: 3}
+	box := func(i int) W {
+		return func(gtx C) D {
+			size := unit.Dp(32 + 16*(i%3))
+			return th.NewFillWithRadius(role(th, flexBoxColors[i%len(flexBoxColors)]), 8, fromage.CornerAll, func(gtx C) D {
+				return D{Size: gtx.Constraints.Constrain(image.Pt(gtx.Dp(size), gtx.Dp(size)))}
+			}).Layout(gtx)
+		}
+	}
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(480))
+		gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(240))
+		gtx.Constraints.Min = gtx.Constraints.Max
+		flex := th.HFlex()
+		if p.Axis == int(layout.Vertical) {
+			flex = th.VFlex()
+		}
+		switch p.Spacing {
+		case "Start":
+			flex.SpaceStart()
+		case "End":
+			flex.SpaceEnd()
+		case "Sides":
+			flex.SpaceSides()
+		case "Around":
+			flex.SpaceAround()
+		case "Between":
+			flex.SpaceBetween()
+		default:
+			flex.SpaceEvenly()
+		}
+		switch p.Alignment {
+		case "Start":
+			flex.AlignStart()
+		case "End":
+			flex.AlignEnd()
+		default:
+			flex.AlignMiddle()
+		}
+		for i := 0; i < p.Children; i++ {
+			flex.Rigid(box(i))
+		}
+		return th.NewBorder().Color(th.Colors.OutlineVariant()).CornerRadius(8).Widget(flex.Layout).Layout(gtx)
+	}
+	snippet := func() string {
+		constructor := "th.HFlex()"
+		if p.Axis == int(layout.Vertical) {
+			constructor = "th.VFlex()"
+		}
+		setters := []string{"Space" + p.Spacing + "()", "Align" + p.Alignment + "()"}
+		for i := 0; i < p.Children; i++ {
+			setters = append(setters, "Rigid(box"+strconv.Itoa(i+1)+")")
+		}
+		return chain(constructor, append(setters, "Layout(gtx)")...)
+	}
+	return newStory(w, "Flex", p, preview, snippet)
+}
+
+// drawerPositionNames are the Go names of the drawer positions, in option order
+var drawerPositionNames = []string{"fromage.DrawerLeft", "fromage.DrawerRight", "fromage.DrawerTop", "fromage.DrawerBottom"}
+
+type drawerProps struct {
+	Position int  `form:"options=Left|Right|Top|Bottom"`
+	Width    int  `form:"label=Width of side drawers,min=120,max=480"`
+	Height   int  `form:"label=Height of top and bottom drawers,min=80,max=400"`
+	Blocking bool `form:"widget=switch"`
+}
+
+// sideDrawer reports whether a drawer position is on the left or right
+func (p *drawerProps) sideDrawer() bool {
+	pos := fromage.DrawerPosition(p.Position)
+	return pos == fromage.DrawerLeft || pos == fromage.DrawerRight
+}
+
+func drawerStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &drawerProps{Width: 240, Height: 160, Blocking: true}
+	drawer := w.NewDrawer()
+	drawer.Content(func(gtx C) D {
+		return layout.UniformInset(16).Layout(gtx, func(gtx C) D {
+			return th.VFlex().
+				Rigid(th.H6("Drawer").Color(th.Colors.OnSurface()).Layout).
+				Rigid(th.Body2("Click outside to close it.").Color(th.Colors.OnSurfaceVariant()).Layout).
+				Layout(gtx)
+		})
+	})
+	open := th.NewButtonLayout().OnClick(drawer.Show)
+	preview := func(gtx C) D {
+		drawer.Position(fromage.DrawerPosition(p.Position)).
+			Width(unit.Dp(p.Width)).
+			Height(unit.Dp(p.Height)).
+			Blocking(p.Blocking)
+		open.Background(th.Colors.Primary()).Widget(padded(th.Body1("Open drawer").Color(th.Colors.OnPrimary())))
+		gtx.Constraints.Min = gtx.Constraints.Max
+		return layout.Stack{}.Layout(gtx,
+			layout.Stacked(func(gtx C) D { return layout.Center.Layout(gtx, open.Layout) }),
+			layout.Expanded(drawer.Layout),
+		)
+	}
+	snippet := func() string {
+		return chain("w.NewDrawer()",
+			call("Position", drawerPositionNames[p.Position]),
+			when(p.sideDrawer(), call("Width", dp(p.Width))),
+			when(!p.sideDrawer(), call("Height", dp(p.Height))),
+			call("Blocking", strconv.FormatBool(p.Blocking)),
+			"Content(content)",
+		)
+	}
+	return newStory(w, "Drawer", p, preview, snippet)
+}
+
+type modalProps struct {
+	ScrimDarkness float32 `form:"min=0,max=1"`
+}
+
+func modalStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &modalProps{ScrimDarkness: 0.5}
+	modals := th.NewModalStack()
+	closeButton := th.NewButtonLayout().OnClick(modals.Pop)
+	content := func(gtx C) D {
+		closeButton.Background(th.Colors.Primary()).Widget(padded(th.Body1("Close").Color(th.Colors.OnPrimary())))
+		return th.NewCardWithColor(th.Colors.Surface(), func(gtx C) D {
+			return th.VFlex().AlignMiddle().
+				Rigid(th.H6("Modal").Color(th.Colors.OnSurface()).Layout).
+				Rigid(func(gtx C) D {
+					return layout.Inset{Top: 8, Bottom: 16}.Layout(gtx,
+						th.Body2("Modals block the content under their scrim.").Color(th.Colors.OnSurfaceVariant()).Layout)
+				}).
+				Rigid(closeButton.Layout).
+				Layout(gtx)
+		}).Padding(24).CornerRadius(12).Layout(gtx)
+	}
+	open := th.NewButtonLayout().OnClick(func() { modals.Push(content, modals.Pop) })
+	preview := func(gtx C) D {
+		modals.ScrimDarkness(p.ScrimDarkness)
+		open.Background(th.Colors.Primary()).Widget(padded(th.Body1("Open modal").Color(th.Colors.OnPrimary())))
+		gtx.Constraints.Min = gtx.Constraints.Max
+		return layout.Stack{}.Layout(gtx,
+			layout.Stacked(func(gtx C) D { return layout.Center.Layout(gtx, open.Layout) }),
+			layout.Expanded(modals.Layout),
+		)
+	}
+	snippet := func() string {
+		return chain("modals := th.NewModalStack()", call("ScrimDarkness", num(p.ScrimDarkness))) +
+			"\nmodals.Push(content, modals.Pop)"
+	}
+	return newStory(w, "Modal", p, preview, snippet)
+}
+
+func globalMenuStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	chosen := "nothing yet"
+	menu := th.NewGlobalMenu().
+		AddItem("Slice", func() { chosen = "Slice" }).
+		AddItem("Arrange", func() { chosen = "Arrange" }).
+		AddItem("Serve", func() { chosen = "Serve" })
+	preview := func(gtx C) D {
+		gtx.Constraints.Min = gtx.Constraints.Max
+		// Keep the menu's scrim and right-click area inside the preview
+		defer clip.Rect{Max: gtx.Constraints.Max}.Push(gtx.Ops).Pop()
+		d := layout.Center.Layout(gtx, func(gtx C) D {
+			return th.VFlex().AlignMiddle().
+				Rigid(th.Body1("Right-click anywhere here to open the menu.").Color(th.Colors.OnSurface()).Layout).
+				Rigid(func(gtx C) D {
+					return layout.Inset{Top: 12}.Layout(gtx,
+						th.Caption("Chosen: "+chosen).Color(th.Colors.OnSurfaceVariant()).Layout)
+				}).
+				Layout(gtx)
+		})
+		menu.Layout(gtx)
+		return D{Size: gtx.Constraints.Max, Baseline: d.Baseline}
+	}
+	snippet := func() string {
+		return chain("th.NewGlobalMenu()",
+			`AddItem("Slice", slice)`,
+			`AddItem("Arrange", arrange)`,
+			`AddItem("Serve", serve)`,
+		) + "\n// Lay it out every frame, over the area it opens in\nmenu.Layout(gtx)"
+	}
+	return newStory(w, "Context menu", nil, preview, snippet)
+}
+
+// textField lays out a bound editor in an outlined box, synchronizing it first
+func textField(th *fromage.Theme, e *fromage.BoundEditor) W {
+	return func(gtx C) D {
+		e.Sync()
+		textColor := op.Record(gtx.Ops)
+		paint.ColorOp{Color: th.Colors.OnSurface()}.Add(gtx.Ops)
+		textMaterial := textColor.Stop()
+		selectionColor := op.Record(gtx.Ops)
+		paint.ColorOp{Color: th.Colors.PrimaryContainer()}.Add(gtx.Ops)
+		selectMaterial := selectionColor.Stop()
+		return th.NewBorder().Color(th.Colors.Outline()).CornerRadius(4).Widget(func(gtx C) D {
+			return layout.UniformInset(8).Layout(gtx, func(gtx C) D {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				return e.Layout(gtx, th.Shaper, font.Font{}, unit.Sp(th.TextSize), textMaterial, selectMaterial)
+			})
+		}).Layout(gtx)
+	}
+}
+
+// takenNames are the names the form story's simulated server rejects
+var takenNames = []string{"admin", "root"}
+
+// emailPattern is a loose check that a string looks like an email address
+const emailPattern = `^[^@\s]+@[^@\s]+$`
+
+func formStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	name, email := fromage.NewValue(""), fromage.NewValue("")
+	nameEditor := th.BindEditor(&widget.Editor{SingleLine: true}, name)
+	emailEditor := th.BindEditor(&widget.Editor{SingleLine: true}, email)
+	welcome := ""
+	submit := th.TextButton("Sign up")
+	form := th.NewForm().SubmitButton(submit).OnSubmit(func() { welcome = "Welcome, " + name.Get() })
+	nameField := fromage.NewFormField(form, name, textField(th, nameEditor), fromage.Required[string]()).
+		Focus(nameEditor.Editor).
+		Async(func(ctx context.Context, value string) error {
+			// Stands in for a request to a server
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			for _, taken := range takenNames {
+				if strings.EqualFold(value, taken) {
+					return errors.New("name is taken")
+				}
+			}
+			return nil
+		})
+	emailField := fromage.NewFormField(form, email, textField(th, emailEditor),
+		fromage.Required[string](), fromage.Pattern(emailPattern, "not an email address")).
+		Focus(emailEditor.Editor)
+	labelled := func(label string, field W) W {
+		return func(gtx C) D {
+			return layout.Inset{Bottom: 12}.Layout(gtx, func(gtx C) D {
+				return th.VFlex().
+					Rigid(th.Caption(label).Color(th.Colors.OnSurfaceVariant()).Layout).
+					Rigid(field).
+					Layout(gtx)
+			})
+		}
+	}
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(360))
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return form.Layout(gtx, func(gtx C) D {
+			return th.VFlex().
+				Rigid(labelled("Name", nameField.Layout)).
+				Rigid(labelled("Email", emailField.Layout)).
+				Rigid(submit.Layout).
+				Rigid(func(gtx C) D {
+					return layout.Inset{Top: 12}.Layout(gtx, th.Body2(welcome).Color(th.Colors.OnSurface()).Layout)
+				}).
+				Layout(gtx)
+		})
+	}
+	snippet := func() string {
+		return chain("form := th.NewForm()", "SubmitButton(submit)", "OnSubmit(signUp)") +
+			"\n" + chain("fromage.NewFormField(form, name, nameInput, fromage.Required[string]())",
+			"Focus(nameEditor.Editor)", "Async(checkNameFree)") +
+			"\n" + chain(`fromage.NewFormField(form, email, emailInput, fromage.Required[string](), fromage.Pattern(emailPattern, "not an email address"))`,
+			"Focus(emailEditor.Editor)")
+	}
+	return newStory(w, "Form", nil, preview, snippet)
+}
+
+// cheeseOrder is the struct the struct form story edits
+type cheeseOrder struct {
+	Cheese string      `form:"options=Brie|Comté|Roquefort"`
+	Wheels int         `form:"min=1,max=12"`
+	Aged   float32     `form:"label=Aged (years),min=0,max=5,step=0.5"`
+	Gift   bool        `form:"label=Gift wrap,widget=switch"`
+	Ribbon color.NRGBA `form:"label=Ribbon color"`
+}
+
+func structFormStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	order := &cheeseOrder{Cheese: "Comté", Wheels: 1, Ribbon: color.NRGBA{R: 0xb3, G: 0x26, B: 0x1e, A: 0xff}}
+	edited := "none yet"
+	form, err := w.NewStructForm(order)
+	if err != nil {
+		return nil, fmt.Errorf("struct form story: %w", err)
+	}
+	form.OnChange(func(field string) { edited = field })
+	preview := func(gtx C) D {
+		gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(420))
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return th.VFlex().
+			Rigid(form.Layout).
+			Rigid(func(gtx C) D {
+				summary := fmt.Sprintf("%d × %s, aged %.1f years, gift wrap %v; last edited %s",
+					order.Wheels, order.Cheese, order.Aged, order.Gift, edited)
+				return layout.Inset{Top: 12}.Layout(gtx, th.Caption(summary).Color(th.Colors.OnSurfaceVariant()).Layout)
+			}).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return `type cheeseOrder struct {
+	Cheese string      ` + "`form:\"options=Brie|Comté|Roquefort\"`" + `
+	Wheels int         ` + "`form:\"min=1,max=12\"`" + `
+	Aged   float32     ` + "`form:\"label=Aged (years),min=0,max=5,step=0.5\"`" + `
+	Gift   bool        ` + "`form:\"label=Gift wrap,widget=switch\"`" + `
+	Ribbon color.NRGBA ` + "`form:\"label=Ribbon color\"`" + `
+}
+
+form, err := w.NewStructForm(&order)`
+	}
+	return newStory(w, "Struct form", nil, preview, snippet)
+}
+
+type asyncProps struct {
+	Delay int  `form:"label=Delay (ms),min=0,max=3000"`
+	Fail  bool `form:"label=Fail the request,widget=switch"`
+}
+
+func asyncStory(w *fromage.Window) (*story, error) {
+	th := w.Theme
+	p := &asyncProps{Delay: 1500}
+	// The settings are read on the work queue, so they are copied into values each frame
+	delay, fail := fromage.NewValue(p.Delay), fromage.NewValue(p.Fail)
+	loader := fromage.NewAsync(th, func(ctx context.Context) (string, error) {
+		select {
+		case <-time.After(time.Duration(delay.Get()) * time.Millisecond):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if fail.Get() {
+			return "", errors.New("the cheese cellar is not answering")
+		}
+		return "Loaded 42 cheeses", nil
+	})
+	reload := th.TextButton("Reload").OnClick(loader.Retry)
+	preview := func(gtx C) D {
+		delay.Set(p.Delay)
+		fail.Set(p.Fail)
+		return th.VFlex().AlignMiddle().
+			Rigid(loader.Layout).
+			Rigid(func(gtx C) D { return layout.Inset{Top: 16}.Layout(gtx, reload.Layout) }).
+			Layout(gtx)
+	}
+	snippet := func() string {
+		return "loader := fromage.NewAsync(th, loadCheeses)\n" +
+			chain(`reload := th.TextButton("Reload")`, "OnClick(loader.Retry)")
+	}
+	return newStory(w, "Async", p, preview, snippet)
+}
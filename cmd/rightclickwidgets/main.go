@@ -12,7 +12,6 @@ import (
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 )
 
 // Import aliases from fromage package
@@ -49,7 +48,7 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 
 	// Initialize application state
 	appState = &AppState{
@@ -76,27 +75,9 @@ func main() {
 		app.Size(unit.Dp(800), unit.Dp(600)),
 		app.Title("Right-Click Widget Demo"),
 	)
-	w.Run(loop(w.Window, th))
-}
-
-func loop(w *app.Window, th *fromage.Theme) func() {
-	return func() {
-		var ops op.Ops
-		// Create a fromage window wrapper
-		fromageWindow := &fromage.Window{Window: w, Theme: th}
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-				mainUI(gtx, th, fromageWindow)
-				e.Frame(gtx.Ops)
-			}
-		}
-	}
+	w.RunFrames(func(gtx layout.Context) {
+		mainUI(gtx, th, w)
+	})
 }
 
 func mainUI(gtx layout.Context, th *fromage.Theme, w *fromage.Window) {
@@ -130,10 +111,8 @@ func mainUI(gtx layout.Context, th *fromage.Theme, w *fromage.Window) {
 
 	// Global menu now handles events internally through EventHandler
 
-	// Layout the global menu
-	if appState.globalMenu.IsVisible() {
-		appState.globalMenu.Layout(gtx)
-	}
+	// Layout the global menu every frame so right-clicks open it
+	appState.globalMenu.Layout(gtx)
 
 	// Layout all widgets
 	for _, widget := range appState.widgets {
@@ -0,0 +1,186 @@
+// Command screenshots renders the demos under cmd headlessly to PNG files, at each
+// combination of the given sizes, densities and theme modes. Each demo whose window allows
+// screenshots with Window.AllowScreenshots is built once and run in screenshot mode, which
+// lays out its UI offscreen at a fixed frame time instead of opening a window, so the
+// images only change when the visuals do and can be diffed.
+// Run it from the repository root:
+//
+//	go run ./cmd/screenshots -sizes 800x600,1200x800 -scales 1,2
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mleku/fromage/internal/offscreen"
+	"github.com/mleku/fromage/internal/screenshot"
+	"lol.mleku.dev/chk"
+	"lol.mleku.dev/log"
+)
+
+func main() {
+	out := flag.String("out", "screenshots", "directory to write the images to")
+	sizes := flag.String("sizes", "1200x800", "comma separated window sizes in dp, as WIDTHxHEIGHT")
+	scales := flag.String("scales", "1,2", "comma separated pixels per dp")
+	modes := flag.String("modes", "light,dark", "comma separated theme modes: light, dark")
+	only := flag.String("demos", "", "comma separated demos to render, instead of every demo under cmd")
+	flag.Parse()
+
+	// A demo inheriting the environment must not run the tool again
+	if os.Getenv(screenshot.PathEnv) != "" {
+		fmt.Fprintf(os.Stderr, "%s is set; the screenshots tool does not render itself\n", screenshot.PathEnv)
+		os.Exit(2)
+	}
+	specs, err := parseSpecs(*sizes, *scales, *modes)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	demos, err := findDemos("cmd", *only)
+	if chk.E(err) {
+		os.Exit(1)
+	}
+	if len(demos) == 0 {
+		fmt.Fprintln(os.Stderr, "no demos found; run from the repository root")
+		os.Exit(2)
+	}
+	if err = os.MkdirAll(*out, 0o755); chk.E(err) {
+		os.Exit(1)
+	}
+	bin, err := os.MkdirTemp("", "fromage-screenshots")
+	if chk.E(err) {
+		os.Exit(1)
+	}
+	defer os.RemoveAll(bin)
+
+	failed := false
+	for _, demo := range demos {
+		exe := filepath.Join(bin, demo)
+		build := exec.Command("go", "build", "-o", exe, "./"+filepath.Join("cmd", demo))
+		build.Stdout, build.Stderr = os.Stdout, os.Stderr
+		if err = build.Run(); err != nil {
+			log.E.F("building %s: %v", demo, err)
+			failed = true
+			continue
+		}
+		for _, spec := range specs {
+			spec.Path = filepath.Join(*out, imageName(demo, spec))
+			if err = render(exe, spec); err != nil {
+				log.E.F("rendering %s: %v", spec.Path, err)
+				failed = true
+				continue
+			}
+			log.I.F("wrote %s", spec.Path)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// render runs a demo in screenshot mode, on Mesa's software rasterizer unless the
+// environment already chooses a driver, so images are the same with or without a GPU.
+// The demo only logs a failed render, so it fails when the demo leaves no image.
+func render(exe string, spec screenshot.Spec) error {
+	if err := os.Remove(spec.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	cmd := exec.Command(exe)
+	cmd.Env = append(os.Environ(), screenshot.Env(spec)...)
+	for _, env := range offscreen.SoftwareEnv {
+		if _, ok := os.LookupEnv(env[0]); !ok {
+			cmd.Env = append(cmd.Env, env[0]+"="+env[1])
+		}
+	}
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(spec.Path); err != nil {
+		return errors.New("the demo wrote no image")
+	}
+	return nil
+}
+
+// imageName names the image of a demo such as buttons-1200x800@2x-dark.png
+func imageName(demo string, spec screenshot.Spec) string {
+	scale := strconv.FormatFloat(float64(spec.Scale), 'g', -1, 32)
+	return fmt.Sprintf("%s-%dx%d@%sx-%s.png", demo, spec.Size.X, spec.Size.Y, scale, spec.Mode)
+}
+
+// parseSpecs returns every combination of the sizes, scales and modes
+func parseSpecs(sizes, scales, modes string) ([]screenshot.Spec, error) {
+	var specs []screenshot.Spec
+	for _, size := range split(sizes) {
+		pt, err := screenshot.ParseSize(size)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range split(scales) {
+			scale, err := strconv.ParseFloat(s, 32)
+			if err != nil || scale <= 0 {
+				return nil, fmt.Errorf("invalid scale %q", s)
+			}
+			for _, mode := range split(modes) {
+				if mode != "light" && mode != "dark" {
+					return nil, fmt.Errorf("invalid theme mode %q, want light or dark", mode)
+				}
+				specs = append(specs, screenshot.Spec{Size: pt, Scale: float32(scale), Mode: mode})
+			}
+		}
+	}
+	if len(specs) == 0 {
+		return nil, errors.New("no sizes, scales or modes given")
+	}
+	return specs, nil
+}
+
+// findDemos returns the commands under dir whose window allows screenshots, limited to
+// only if it is not empty
+func findDemos(dir, only string) ([]string, error) {
+	wanted, missing := map[string]bool{}, map[string]bool{}
+	for _, name := range split(only) {
+		wanted[name], missing[name] = true, true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var demos []string
+	for _, e := range entries {
+		if !e.IsDir() || e.Name() == self || (len(wanted) > 0 && !wanted[e.Name()]) {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, e.Name(), "main.go"))
+		if err != nil || !strings.Contains(string(src), ".AllowScreenshots()") {
+			continue
+		}
+		delete(missing, e.Name())
+		demos = append(demos, e.Name())
+	}
+	for name := range missing {
+		return nil, fmt.Errorf("no demo %q renders screenshots", name)
+	}
+	return demos, nil
+}
+
+// self is the tool's own directory under cmd, which mentions AllowScreenshots but is no
+// demo
+const self = "screenshots"
+
+// split splits a comma separated list, dropping empty items
+func split(list string) []string {
+	var items []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -7,11 +7,9 @@ import (
 	"gio.mleku.dev/app"
 	"gio.mleku.dev/font/gofont"
 	"gio.mleku.dev/layout"
-	"gio.mleku.dev/op"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 	"github.com/mleku/fromage"
-	"lol.mleku.dev/chk"
 )
 
 // Import aliases from fromage package
@@ -30,15 +28,15 @@ func main() {
 		fromage.ThemeModeDark,
 	)
 
-	w := fromage.NewWindow(th)
+	w := fromage.NewWindow(th).AllowScreenshots()
 	w.Option(app.Size(
 		unit.Dp(800), unit.Dp(1600)),
 		app.Title("Scrollbar Demo"),
 	)
-	w.Run(loop(w.Window, th, w))
+	w.RunFrames(frame(th, w))
 }
 
-func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
+func frame(th *fromage.Theme, window *fromage.Window) func(gtx layout.Context) {
 	// Float slider to control viewport proportion
 	viewportSlider := th.NewFloat().SetRange(0.1, 1.0).SetValue(0.5)
 
@@ -52,36 +50,23 @@ func loop(w *app.Window, th *fromage.Theme, window *fromage.Window) func() {
 	var horizontalPos float32 = 0.0
 	var verticalPos float32 = 0.0
 
-	return func() {
-		var ops op.Ops
-		for {
-			switch e := w.Event().(type) {
-			case app.DestroyEvent:
-				chk.E(e.Err)
-				return
-			case app.FrameEvent:
-				gtx := app.NewContext(&ops, e)
-				th.Pool.Reset() // Reset pool at the beginning of each frame
-
-				// Update viewport proportion if slider changed
-				if viewportSlider.Changed() {
-					viewportProportion = viewportSlider.Value()
-					horizontalScrollbar.SetViewport(viewportProportion)
-					verticalScrollbar.SetViewport(viewportProportion)
-				}
-
-				// Update scrollbar positions if they changed
-				if horizontalScrollbar.Changed() {
-					horizontalPos = horizontalScrollbar.Position()
-				}
-				if verticalScrollbar.Changed() {
-					verticalPos = verticalScrollbar.Position()
-				}
+	return func(gtx layout.Context) {
+		// Update viewport proportion if slider changed
+		if viewportSlider.Changed() {
+			viewportProportion = viewportSlider.Value()
+			horizontalScrollbar.SetViewport(viewportProportion)
+			verticalScrollbar.SetViewport(viewportProportion)
+		}
 
-				mainUI(gtx, th, window, viewportSlider, horizontalScrollbar, verticalScrollbar, viewportProportion, horizontalPos, verticalPos)
-				e.Frame(gtx.Ops)
-			}
+		// Update scrollbar positions if they changed
+		if horizontalScrollbar.Changed() {
+			horizontalPos = horizontalScrollbar.Position()
 		}
+		if verticalScrollbar.Changed() {
+			verticalPos = verticalScrollbar.Position()
+		}
+
+		mainUI(gtx, th, window, viewportSlider, horizontalScrollbar, verticalScrollbar, viewportProportion, horizontalPos, verticalPos)
 	}
 }
 
@@ -0,0 +1,69 @@
+// Command themegen prints a fromage color scheme generated from a seed color (or the
+// dominant color of an image) as CSS variables, W3C design tokens, Go source or a
+// fromage theme file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"gio.mleku.dev/unit"
+	"github.com/mleku/fromage"
+	"lol.mleku.dev/chk"
+)
+
+func main() {
+	seed := flag.String("seed", "6750A4", "seed color as RRGGBB hex")
+	img := flag.String("image", "", "derive the seed color from a PNG or JPEG image instead")
+	formatName := flag.String("format", "css", "output format: css, tokens, go or theme")
+	pkg := flag.String("pkg", "theme", "package name for -format go")
+	flag.Parse()
+
+	var colors *fromage.Colors
+	if *img != "" {
+		f, err := os.Open(*img)
+		if chk.E(err) {
+			os.Exit(1)
+		}
+		decoded, _, err := image.Decode(f)
+		f.Close()
+		if chk.E(err) {
+			os.Exit(1)
+		}
+		colors = fromage.NewColorsFromImage(decoded)
+	} else {
+		v, err := strconv.ParseUint(strings.TrimPrefix(*seed, "#"), 16, 32)
+		if err != nil || v > 0xFFFFFF {
+			fmt.Fprintf(os.Stderr, "invalid seed color %q, want RRGGBB hex\n", *seed)
+			os.Exit(2)
+		}
+		colors = fromage.NewColorsFromSeed(uint32(v))
+	}
+
+	var err error
+	switch *formatName {
+	case "css":
+		err = colors.ExportCSS(os.Stdout)
+	case "tokens":
+		err = colors.ExportDesignTokens(os.Stdout)
+	case "go":
+		err = colors.ExportGo(os.Stdout, *pkg)
+	case "theme":
+		th := fromage.NewTheme(context.Background(), fromage.NewColors, nil, unit.Dp(16))
+		th.Colors = colors
+		err = th.Save(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q, want css, tokens, go or theme\n", *formatName)
+		os.Exit(2)
+	}
+	if chk.E(err) {
+		os.Exit(1)
+	}
+}
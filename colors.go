@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"image/color"
 	"math"
+	"reflect"
+	"time"
 )
 
 // ThemeMode represents the theme variant
@@ -12,9 +14,21 @@ type ThemeMode int
 const (
 	ThemeModeLight ThemeMode = iota
 	ThemeModeDark
-	ThemeModeAuto // Follows system preference
+	ThemeModeAuto              // Follows system preference
+	ThemeModeHighContrastLight // Black on white with AAA (7:1) contrast for every on-role
+	ThemeModeHighContrastDark  // White on black with AAA (7:1) contrast for every on-role
 )
 
+// IsDark returns true for the dark and high-contrast dark modes
+func (m ThemeMode) IsDark() bool {
+	return m == ThemeModeDark || m == ThemeModeHighContrastDark
+}
+
+// IsHighContrast returns true for the high-contrast modes
+func (m ThemeMode) IsHighContrast() bool {
+	return m == ThemeModeHighContrastLight || m == ThemeModeHighContrastDark
+}
+
 // Material Design color roles
 type ColorRoles struct {
 	// Primary colors
@@ -153,7 +167,19 @@ type Colors struct {
 	roles       ColorRoles
 	palette     ColorPalette
 	themeMode   ThemeMode
-	surfaceTint color.NRGBA // Custom surface tint color
+	system      ColorScheme              // System color scheme preference used by ThemeModeAuto
+	surfaceTint color.NRGBA              // Custom surface tint color
+	minContrast float64                  // Minimum contrast ratio enforced for on-roles (0 = not enforced)
+	customRoles map[ThemeMode]ColorRoles // Role sets that replace the palette-derived roles
+	vision      ColorVision              // Color vision deficiency simulated on the roles
+	// Theme transition state
+	transition      time.Duration // Cross-fade duration for theme mode changes (0 = instant)
+	transitionStart time.Time     // Frame time the current cross-fade started, zero before its first frame
+	// Wall clock time of the mode change, finishing a cross-fade that never gets a frame
+	transitionRequested time.Time
+	transitionFrom      ColorRoles // Roles shown when the cross-fade started
+	transitioning       bool       // Whether a cross-fade is in progress
+	shown               ColorRoles // Interpolated roles shown during a cross-fade
 }
 
 func NewColors() *Colors {
@@ -307,14 +333,24 @@ func (t *Colors) initCustomPalette(primary, secondary uint32) {
 
 // Initialize Material Design 3 color roles based on theme mode
 func (t *Colors) initRoles() {
-	switch t.themeMode {
-	case ThemeModeDark:
-		t.initDarkRoles()
-	case ThemeModeLight:
-		fallthrough
-	default:
-		t.initLightRoles()
+	if roles, ok := t.customRoles[t.EffectiveMode()]; ok {
+		t.roles = roles
+	} else {
+		switch t.EffectiveMode() {
+		case ThemeModeDark:
+			t.initDarkRoles()
+		case ThemeModeHighContrastLight:
+			t.initHighContrastLightRoles()
+		case ThemeModeHighContrastDark:
+			t.initHighContrastDarkRoles()
+		case ThemeModeLight:
+			fallthrough
+		default:
+			t.initLightRoles()
+		}
 	}
+	t.enforceContrast()
+	t.simulateColorVision()
 }
 
 // Initialize light theme color roles
@@ -423,46 +459,140 @@ func (t *Colors) initDarkRoles() {
 	t.roles.SurfaceTint = t.palette.Primary200
 }
 
+// Initialize high-contrast light theme color roles
+func (t *Colors) initHighContrastLightRoles() {
+	white, black := hex("#FFFFFF"), hex("#000000")
+
+	// Accent roles use the darkest palette steps so white text reaches 7:1
+	t.roles.Primary = t.palette.Primary950
+	t.roles.OnPrimary = white
+	t.roles.PrimaryContainer = t.palette.Primary50
+	t.roles.OnPrimaryContainer = black
+
+	t.roles.Secondary = t.palette.Secondary950
+	t.roles.OnSecondary = white
+	t.roles.SecondaryContainer = t.palette.Secondary50
+	t.roles.OnSecondaryContainer = black
+
+	t.roles.Tertiary = t.palette.Tertiary950
+	t.roles.OnTertiary = white
+	t.roles.TertiaryContainer = t.palette.Tertiary50
+	t.roles.OnTertiaryContainer = black
+
+	t.roles.Error = t.palette.Error950
+	t.roles.OnError = white
+	t.roles.ErrorContainer = t.palette.Error50
+	t.roles.OnErrorContainer = black
+
+	// Background and surfaces are pure white without surface tint
+	t.roles.Background = white
+	t.roles.OnBackground = black
+	t.roles.Surface = white
+	t.roles.OnSurface = black
+	t.roles.SurfaceVariant = t.palette.Neutral100
+	t.roles.OnSurfaceVariant = black
+
+	// Outlines are as strong as text
+	t.roles.Outline = black
+	t.roles.OutlineVariant = t.palette.Neutral800
+
+	t.roles.Shadow = black
+	t.roles.Scrim = black
+
+	t.roles.InverseSurface = black
+	t.roles.InverseOnSurface = white
+	t.roles.InversePrimary = t.palette.Primary100
+
+	t.roles.SurfaceTint = t.palette.Primary950
+}
+
+// Initialize high-contrast dark theme color roles
+func (t *Colors) initHighContrastDarkRoles() {
+	white, black := hex("#FFFFFF"), hex("#000000")
+
+	// Accent roles use the lightest palette steps so black text reaches 7:1
+	t.roles.Primary = t.palette.Primary50
+	t.roles.OnPrimary = black
+	t.roles.PrimaryContainer = t.palette.Primary950
+	t.roles.OnPrimaryContainer = white
+
+	t.roles.Secondary = t.palette.Secondary50
+	t.roles.OnSecondary = black
+	t.roles.SecondaryContainer = t.palette.Secondary950
+	t.roles.OnSecondaryContainer = white
+
+	t.roles.Tertiary = t.palette.Tertiary50
+	t.roles.OnTertiary = black
+	t.roles.TertiaryContainer = t.palette.Tertiary950
+	t.roles.OnTertiaryContainer = white
+
+	t.roles.Error = t.palette.Error50
+	t.roles.OnError = black
+	t.roles.ErrorContainer = t.palette.Error950
+	t.roles.OnErrorContainer = white
+
+	// Background and surfaces are pure black without surface tint
+	t.roles.Background = black
+	t.roles.OnBackground = white
+	t.roles.Surface = black
+	t.roles.OnSurface = white
+	t.roles.SurfaceVariant = t.palette.Neutral900
+	t.roles.OnSurfaceVariant = white
+
+	// Outlines are as strong as text
+	t.roles.Outline = white
+	t.roles.OutlineVariant = t.palette.Neutral200
+
+	t.roles.Shadow = black
+	t.roles.Scrim = black
+
+	t.roles.InverseSurface = white
+	t.roles.InverseOnSurface = black
+	t.roles.InversePrimary = t.palette.Primary900
+
+	t.roles.SurfaceTint = t.palette.Primary50
+}
+
 // Getters for color roles
-func (t *Colors) Primary() color.NRGBA            { return t.roles.Primary }
-func (t *Colors) OnPrimary() color.NRGBA          { return t.roles.OnPrimary }
-func (t *Colors) PrimaryContainer() color.NRGBA   { return t.roles.PrimaryContainer }
-func (t *Colors) OnPrimaryContainer() color.NRGBA { return t.roles.OnPrimaryContainer }
+func (t *Colors) Primary() color.NRGBA            { return t.current().Primary }
+func (t *Colors) OnPrimary() color.NRGBA          { return t.current().OnPrimary }
+func (t *Colors) PrimaryContainer() color.NRGBA   { return t.current().PrimaryContainer }
+func (t *Colors) OnPrimaryContainer() color.NRGBA { return t.current().OnPrimaryContainer }
 
-func (t *Colors) Secondary() color.NRGBA            { return t.roles.Secondary }
-func (t *Colors) OnSecondary() color.NRGBA          { return t.roles.OnSecondary }
-func (t *Colors) SecondaryContainer() color.NRGBA   { return t.roles.SecondaryContainer }
-func (t *Colors) OnSecondaryContainer() color.NRGBA { return t.roles.OnSecondaryContainer }
+func (t *Colors) Secondary() color.NRGBA            { return t.current().Secondary }
+func (t *Colors) OnSecondary() color.NRGBA          { return t.current().OnSecondary }
+func (t *Colors) SecondaryContainer() color.NRGBA   { return t.current().SecondaryContainer }
+func (t *Colors) OnSecondaryContainer() color.NRGBA { return t.current().OnSecondaryContainer }
 
-func (t *Colors) Tertiary() color.NRGBA            { return t.roles.Tertiary }
-func (t *Colors) OnTertiary() color.NRGBA          { return t.roles.OnTertiary }
-func (t *Colors) TertiaryContainer() color.NRGBA   { return t.roles.TertiaryContainer }
-func (t *Colors) OnTertiaryContainer() color.NRGBA { return t.roles.OnTertiaryContainer }
+func (t *Colors) Tertiary() color.NRGBA            { return t.current().Tertiary }
+func (t *Colors) OnTertiary() color.NRGBA          { return t.current().OnTertiary }
+func (t *Colors) TertiaryContainer() color.NRGBA   { return t.current().TertiaryContainer }
+func (t *Colors) OnTertiaryContainer() color.NRGBA { return t.current().OnTertiaryContainer }
 
-func (t *Colors) Error() color.NRGBA            { return t.roles.Error }
-func (t *Colors) OnError() color.NRGBA          { return t.roles.OnError }
-func (t *Colors) ErrorContainer() color.NRGBA   { return t.roles.ErrorContainer }
-func (t *Colors) OnErrorContainer() color.NRGBA { return t.roles.OnErrorContainer }
+func (t *Colors) Error() color.NRGBA            { return t.current().Error }
+func (t *Colors) OnError() color.NRGBA          { return t.current().OnError }
+func (t *Colors) ErrorContainer() color.NRGBA   { return t.current().ErrorContainer }
+func (t *Colors) OnErrorContainer() color.NRGBA { return t.current().OnErrorContainer }
 
-func (t *Colors) Background() color.NRGBA   { return t.roles.Background }
-func (t *Colors) OnBackground() color.NRGBA { return t.roles.OnBackground }
+func (t *Colors) Background() color.NRGBA   { return t.current().Background }
+func (t *Colors) OnBackground() color.NRGBA { return t.current().OnBackground }
 
-func (t *Colors) Surface() color.NRGBA          { return t.roles.Surface }
-func (t *Colors) OnSurface() color.NRGBA        { return t.roles.OnSurface }
-func (t *Colors) SurfaceVariant() color.NRGBA   { return t.roles.SurfaceVariant }
-func (t *Colors) OnSurfaceVariant() color.NRGBA { return t.roles.OnSurfaceVariant }
+func (t *Colors) Surface() color.NRGBA          { return t.current().Surface }
+func (t *Colors) OnSurface() color.NRGBA        { return t.current().OnSurface }
+func (t *Colors) SurfaceVariant() color.NRGBA   { return t.current().SurfaceVariant }
+func (t *Colors) OnSurfaceVariant() color.NRGBA { return t.current().OnSurfaceVariant }
 
-func (t *Colors) Outline() color.NRGBA        { return t.roles.Outline }
-func (t *Colors) OutlineVariant() color.NRGBA { return t.roles.OutlineVariant }
+func (t *Colors) Outline() color.NRGBA        { return t.current().Outline }
+func (t *Colors) OutlineVariant() color.NRGBA { return t.current().OutlineVariant }
 
-func (t *Colors) Shadow() color.NRGBA { return t.roles.Shadow }
-func (t *Colors) Scrim() color.NRGBA  { return t.roles.Scrim }
+func (t *Colors) Shadow() color.NRGBA { return t.current().Shadow }
+func (t *Colors) Scrim() color.NRGBA  { return t.current().Scrim }
 
-func (t *Colors) InverseSurface() color.NRGBA   { return t.roles.InverseSurface }
-func (t *Colors) InverseOnSurface() color.NRGBA { return t.roles.InverseOnSurface }
-func (t *Colors) InversePrimary() color.NRGBA   { return t.roles.InversePrimary }
+func (t *Colors) InverseSurface() color.NRGBA   { return t.current().InverseSurface }
+func (t *Colors) InverseOnSurface() color.NRGBA { return t.current().InverseOnSurface }
+func (t *Colors) InversePrimary() color.NRGBA   { return t.current().InversePrimary }
 
-func (t *Colors) SurfaceTint() color.NRGBA { return t.roles.SurfaceTint }
+func (t *Colors) SurfaceTint() color.NRGBA { return t.current().SurfaceTint }
 
 // Surface tint control methods
 func (t *Colors) SetSurfaceTint(tint color.NRGBA) {
@@ -511,20 +641,84 @@ func (t *Colors) GetSurfaceTintHSV() (hue, saturation, value float32) {
 // Getters for palette colors
 func (t *Colors) Palette() ColorPalette { return t.palette }
 
+// SetPalette replaces the palette and rebuilds the roles from it
+func (t *Colors) SetPalette(palette ColorPalette) *Colors {
+	t.palette = palette
+	t.initRoles()
+	return t
+}
+
+// SetRoles replaces the palette-derived roles of a theme mode with a custom role set
+func (t *Colors) SetRoles(mode ThemeMode, roles ColorRoles) *Colors {
+	if t.customRoles == nil {
+		t.customRoles = make(map[ThemeMode]ColorRoles)
+	}
+	t.customRoles[mode] = roles
+	t.initRoles()
+	return t
+}
+
+// RolesFor returns the target roles of a theme mode without switching to it, and without
+// any color vision simulation
+func (t *Colors) RolesFor(mode ThemeMode) ColorRoles {
+	c := *t
+	c.themeMode = mode
+	c.vision = ColorVisionNormal
+	c.initRoles()
+	return c.roles
+}
+
 // Theme mode methods
 func (t *Colors) ThemeMode() ThemeMode { return t.themeMode }
 
 func (t *Colors) SetThemeMode(mode ThemeMode) {
 	if t.themeMode != mode {
+		from := *t.current()
 		t.themeMode = mode
 		t.initRoles()
+		t.startTransition(from)
+	}
+}
+
+// EffectiveMode returns the theme mode in use, resolving ThemeModeAuto to light or dark
+// from the system color scheme preference
+func (t *Colors) EffectiveMode() ThemeMode {
+	if t.themeMode != ThemeModeAuto {
+		return t.themeMode
+	}
+	if t.system == ColorSchemeDark {
+		return ThemeModeDark
+	}
+	return ThemeModeLight
+}
+
+// SystemColorScheme returns the last known system color scheme preference
+func (t *Colors) SystemColorScheme() ColorScheme { return t.system }
+
+// SetSystemColorScheme records the system color scheme preference, switching the roles
+// when the theme follows the system
+func (t *Colors) SetSystemColorScheme(scheme ColorScheme) {
+	if t.system == scheme {
+		return
+	}
+	from := *t.current()
+	before := t.EffectiveMode()
+	t.system = scheme
+	if t.themeMode == ThemeModeAuto && t.EffectiveMode() != before {
+		t.initRoles()
+		t.startTransition(from)
 	}
 }
 
 func (t *Colors) ToggleTheme() {
-	if t.themeMode == ThemeModeLight {
+	switch t.EffectiveMode() {
+	case ThemeModeHighContrastLight:
+		t.SetThemeMode(ThemeModeHighContrastDark)
+	case ThemeModeHighContrastDark:
+		t.SetThemeMode(ThemeModeHighContrastLight)
+	case ThemeModeLight:
 		t.SetThemeMode(ThemeModeDark)
-	} else {
+	default:
 		t.SetThemeMode(ThemeModeLight)
 	}
 }
@@ -587,7 +781,7 @@ func (t *Colors) blendColors(base, tint color.NRGBA, ratio float64) color.NRGBA
 
 // applySurfaceTint applies a custom tint to the surface color for contrast
 func (t *Colors) applySurfaceTint(baseColor color.NRGBA) color.NRGBA {
-	if t.ThemeMode() == ThemeModeLight {
+	if !t.EffectiveMode().IsDark() {
 		// Light mode: use the brightness complement to work with bright white background
 		return t.invertValue(t.surfaceTint)
 	} else {
@@ -630,10 +824,11 @@ func (t *Colors) hsvToRgb(h, s, v float32) color.NRGBA {
 		r, g, b = c, 0, x
 	}
 
+	// Round rather than truncate, so a color converted to HSV and back is unchanged
 	return color.NRGBA{
-		R: uint8((r + m) * 255),
-		G: uint8((g + m) * 255),
-		B: uint8((b + m) * 255),
+		R: uint8((r+m)*255 + 0.5),
+		G: uint8((g+m)*255 + 0.5),
+		B: uint8((b+m)*255 + 0.5),
 		A: 255,
 	}
 }
@@ -672,3 +867,19 @@ func (t *Colors) rgbToHsv(c color.NRGBA) (float32, float32, float32) {
 
 	return h, s, v
 }
+
+// each calls fn with the field name and a pointer to every color in the role set
+func (r *ColorRoles) each(fn func(name string, c *color.NRGBA)) {
+	v := reflect.ValueOf(r).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fn(v.Type().Field(i).Name, v.Field(i).Addr().Interface().(*color.NRGBA))
+	}
+}
+
+// each calls fn with the field name and a pointer to every color in the palette
+func (p *ColorPalette) each(fn func(name string, c *color.NRGBA)) {
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fn(v.Type().Field(i).Name, v.Field(i).Addr().Interface().(*color.NRGBA))
+	}
+}
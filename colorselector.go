@@ -11,15 +11,18 @@ import (
 
 // ColorSelector provides tone, hue, and saturation sliders
 type ColorSelector struct {
+	theme      *Theme
 	tone       *Float // Tone slider (0-1)
 	hue        *Float // Hue slider (0-1, represents 0-360 degrees)
 	saturation *Float // Saturation slider (0-1)
 	onChange   func(color.NRGBA)
+	bound      binding[color.NRGBA] // observable color the sliders show and edit
 }
 
 // NewColorSelector creates a new color selector
 func (t *Theme) NewColorSelector() *ColorSelector {
 	cs := &ColorSelector{
+		theme:      t,
 		tone:       t.NewFloat().SetRange(0, 1).SetValue(0.5),
 		hue:        t.NewFloat().SetRange(0, 1).SetValue(0), // 0 = red
 		saturation: t.NewFloat().SetRange(0, 1).SetValue(1),
@@ -46,6 +49,13 @@ func (cs *ColorSelector) SetOnChange(fn func(color.NRGBA)) *ColorSelector {
 	return cs
 }
 
+// Bind makes the selector show and edit an observable color
+func (cs *ColorSelector) Bind(v *Value[color.NRGBA]) *ColorSelector {
+	cs.bound.bind(v)
+	v.Invalidates(cs.theme)
+	return cs
+}
+
 // GetColor returns the current selected color
 func (cs *ColorSelector) GetColor() color.NRGBA {
 	// Convert hue from 0-1 to 0-360 degrees
@@ -97,6 +107,11 @@ func (cs *ColorSelector) SetSaturation(saturation float32) *ColorSelector {
 
 // Layout renders the color selector
 func (cs *ColorSelector) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
+	// Follow changes to the bound color
+	if c, ok := cs.bound.pull(); ok {
+		cs.SetColor(c)
+	}
+
 	return th.VFlex().
 		Rigid(func(gtx layout.Context) layout.Dimensions {
 			// Tone slider
@@ -216,7 +231,8 @@ func (cs *ColorSelector) rgbToHsv(c color.NRGBA) (float32, float32, float32) {
 	return h, s, v
 }
 
-// updateColor calls the onChange callback with the current color
+// updateColor writes the current color to the bound value and calls the onChange callback
 func (cs *ColorSelector) updateColor() {
+	cs.bound.push(cs.GetColor())
 	cs.onChange(cs.GetColor())
 }
@@ -0,0 +1,71 @@
+package fromage
+
+import (
+	"image/color"
+)
+
+// ColorVision is a color vision deficiency that can be simulated on the theme colors
+type ColorVision int
+
+const (
+	ColorVisionNormal       ColorVision = iota
+	ColorVisionProtanopia               // No red cones
+	ColorVisionDeuteranopia             // No green cones
+	ColorVisionTritanopia               // No blue cones
+)
+
+// colorVisionMatrices are the Machado, Oliveira and Fernandes (2009) full-severity
+// simulation matrices, applied to linear sRGB
+var colorVisionMatrices = map[ColorVision][3][3]float64{
+	ColorVisionProtanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	ColorVisionDeuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	ColorVisionTritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// SimulateColorVision returns the color as it appears with the given color vision deficiency
+func SimulateColorVision(c color.NRGBA, vision ColorVision) color.NRGBA {
+	m, ok := colorVisionMatrices[vision]
+	if !ok {
+		return c
+	}
+	r, g, b := decodeSRGB(c.R), decodeSRGB(c.G), decodeSRGB(c.B)
+	return color.NRGBA{
+		R: encodeSRGB(m[0][0]*r + m[0][1]*g + m[0][2]*b),
+		G: encodeSRGB(m[1][0]*r + m[1][1]*g + m[1][2]*b),
+		B: encodeSRGB(m[2][0]*r + m[2][1]*g + m[2][2]*b),
+		A: c.A,
+	}
+}
+
+// SetColorVision renders every role color through a color vision deficiency simulation,
+// so screens can be checked for color-blind users. ColorVisionNormal turns it off.
+func (t *Colors) SetColorVision(vision ColorVision) *Colors {
+	from := *t.current()
+	t.vision = vision
+	t.initRoles()
+	t.startTransition(from)
+	return t
+}
+
+// ColorVision returns the simulated color vision deficiency
+func (t *Colors) ColorVision() ColorVision { return t.vision }
+
+// simulateColorVision applies the color vision simulation to the roles
+func (t *Colors) simulateColorVision() {
+	if t.vision == ColorVisionNormal {
+		return
+	}
+	t.roles.each(func(_ string, c *color.NRGBA) { *c = SimulateColorVision(*c, t.vision) })
+}
@@ -0,0 +1,69 @@
+package fromage
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestHighContrastModes(t *testing.T) {
+	for _, mode := range []ThemeMode{ThemeModeHighContrastLight, ThemeModeHighContrastDark} {
+		colors := NewColorsWithMode(mode)
+		for _, f := range colors.AuditContrast(ContrastAAA).Failures() {
+			t.Errorf("Mode %v: %s on %s at %.2f, want at least 7", mode, f.Foreground, f.Background, f.Ratio)
+		}
+		// Custom tints must not leak into high-contrast surfaces
+		colors.SetSurfaceTint(rgb(0x808080))
+		for _, f := range colors.AuditContrast(ContrastAAA).Failures() {
+			t.Errorf("Mode %v with tint: %s on %s at %.2f", mode, f.Foreground, f.Background, f.Ratio)
+		}
+	}
+
+	light := NewColorsWithMode(ThemeModeHighContrastLight)
+	if light.Background() != (color.NRGBA{R: 255, G: 255, B: 255, A: 255}) || light.OnBackground() != (color.NRGBA{A: 255}) {
+		t.Error("Expected high-contrast light to be black on white")
+	}
+	if !ThemeModeHighContrastDark.IsDark() || ThemeModeHighContrastLight.IsDark() {
+		t.Error("Expected IsDark to report only dark modes")
+	}
+
+	light.ToggleTheme()
+	if light.ThemeMode() != ThemeModeHighContrastDark {
+		t.Errorf("Expected toggle to stay high-contrast, got %v", light.ThemeMode())
+	}
+}
+
+func TestSimulateColorVision(t *testing.T) {
+	red, green := rgb(0xD32F2F), rgb(0x388E3C)
+	if got := SimulateColorVision(red, ColorVisionNormal); got != red {
+		t.Errorf("Expected normal vision to leave the color unchanged, got %v", got)
+	}
+	// Red and green become hard to tell apart without red or green cones
+	for _, vision := range []ColorVision{ColorVisionProtanopia, ColorVisionDeuteranopia} {
+		r, g := SimulateColorVision(red, vision), SimulateColorVision(green, vision)
+		_, _, rh := toOKLCH(r)
+		_, _, gh := toOKLCH(g)
+		if d := rh - gh; d > 40 || d < -40 {
+			t.Errorf("Vision %v: expected red and green hues to converge, got %.0f and %.0f", vision, rh, gh)
+		}
+	}
+	white := rgb(0xFFFFFF)
+	if got := SimulateColorVision(white, ColorVisionTritanopia); got != white {
+		t.Errorf("Expected white to stay white, got %v", got)
+	}
+}
+
+func TestColorsSetColorVision(t *testing.T) {
+	colors := NewColors()
+	normal := colors.Roles()
+	colors.SetColorVision(ColorVisionDeuteranopia)
+	if colors.Primary() != SimulateColorVision(normal.Primary, ColorVisionDeuteranopia) {
+		t.Error("Expected roles to be rendered through the simulation")
+	}
+	if colors.RolesFor(ThemeModeLight) != normal {
+		t.Error("Expected RolesFor to return unsimulated roles")
+	}
+	colors.SetColorVision(ColorVisionNormal)
+	if colors.Roles() != normal {
+		t.Error("Expected roles to be restored")
+	}
+}
@@ -0,0 +1,218 @@
+package fromage
+
+import (
+	"image/color"
+	"math"
+	"reflect"
+)
+
+// ContrastLevel is a minimum WCAG 2.x contrast ratio for text
+type ContrastLevel float64
+
+// WCAG 2.x contrast levels
+const (
+	// ContrastAALarge is the AA minimum for large text (18pt, or 14pt bold)
+	ContrastAALarge ContrastLevel = 3
+	// ContrastAA is the AA minimum for body text
+	ContrastAA ContrastLevel = 4.5
+	// ContrastAAA is the AAA minimum for body text
+	ContrastAAA ContrastLevel = 7
+)
+
+// ContrastResult is the contrast of one on-role drawn on its background role
+type ContrastResult struct {
+	// Background and Foreground are the role names, e.g. "Primary" and "OnPrimary"
+	Background string
+	Foreground string
+	// BackgroundColor and ForegroundColor are the role colors that were checked
+	BackgroundColor color.NRGBA
+	ForegroundColor color.NRGBA
+	// Ratio is the WCAG 2.x contrast ratio (1 to 21)
+	Ratio float64
+	// APCA is the APCA lightness contrast (Lc, roughly -108 to 106)
+	APCA float64
+	// Pass is true when Ratio meets the audited level
+	Pass bool
+	// Suggested is the foreground re-toned to meet the level, equal to ForegroundColor on a pass
+	Suggested color.NRGBA
+	// SuggestedRatio is the WCAG 2.x contrast ratio of Suggested
+	SuggestedRatio float64
+}
+
+// ContrastReport is the result of auditing every role and on-role pair
+type ContrastReport []ContrastResult
+
+// Failures returns the results that did not meet the audited level
+func (r ContrastReport) Failures() ContrastReport {
+	var failures ContrastReport
+	for _, result := range r {
+		if !result.Pass {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// OK returns true when every pair met the audited level
+func (r ContrastReport) OK() bool { return len(r.Failures()) == 0 }
+
+// AuditContrast checks every role and on-role pair of the current theme mode against the
+// given level, suggesting an adjusted on-role tone for each failure
+func (t *Colors) AuditContrast(level ContrastLevel) ContrastReport {
+	report := make(ContrastReport, 0, len(rolePairs))
+	for _, pair := range rolePairs {
+		bg, fg := *t.roles.field(pair[0]), *t.roles.field(pair[1])
+		result := ContrastResult{
+			Background:      pair[0],
+			Foreground:      pair[1],
+			BackgroundColor: bg,
+			ForegroundColor: fg,
+			Ratio:           ContrastRatio(fg, bg),
+			APCA:            APCAContrast(fg, bg),
+		}
+		result.Pass = result.Ratio >= float64(level)
+		result.Suggested = ensureContrast(fg, bg, float64(level))
+		result.SuggestedRatio = ContrastRatio(result.Suggested, bg)
+		report = append(report, result)
+	}
+	return report
+}
+
+// SetMinContrast makes every on-role be re-toned as needed to meet the given level, now and
+// whenever the roles are rebuilt (mode changes, surface tint changes). 0 turns it off.
+func (t *Colors) SetMinContrast(level ContrastLevel) *Colors {
+	t.minContrast = float64(level)
+	t.initRoles()
+	return t
+}
+
+// MinContrast returns the contrast level enforced for on-roles (0 = not enforced)
+func (t *Colors) MinContrast() ContrastLevel { return ContrastLevel(t.minContrast) }
+
+// rolePairs lists every background role with the role drawn on top of it
+var rolePairs = [][2]string{
+	{"Primary", "OnPrimary"},
+	{"PrimaryContainer", "OnPrimaryContainer"},
+	{"Secondary", "OnSecondary"},
+	{"SecondaryContainer", "OnSecondaryContainer"},
+	{"Tertiary", "OnTertiary"},
+	{"TertiaryContainer", "OnTertiaryContainer"},
+	{"Error", "OnError"},
+	{"ErrorContainer", "OnErrorContainer"},
+	{"Background", "OnBackground"},
+	{"Surface", "OnSurface"},
+	{"SurfaceVariant", "OnSurfaceVariant"},
+	{"InverseSurface", "InverseOnSurface"},
+}
+
+// field returns a pointer to the role color with the given field name
+func (r *ColorRoles) field(name string) *color.NRGBA {
+	f := reflect.ValueOf(r).Elem().FieldByName(name)
+	if !f.IsValid() {
+		return nil
+	}
+	return f.Addr().Interface().(*color.NRGBA)
+}
+
+// enforceContrast moves the tone of every on-role away from its background role until
+// the pair reaches the minimum contrast ratio, which is at least AAA in high-contrast modes
+func (t *Colors) enforceContrast() {
+	level := t.minContrast
+	if t.EffectiveMode().IsHighContrast() {
+		level = math.Max(level, float64(ContrastAAA))
+	}
+	if level <= 0 {
+		return
+	}
+	for _, pair := range rolePairs {
+		bg, fg := t.roles.field(pair[0]), t.roles.field(pair[1])
+		*fg = ensureContrast(*fg, *bg, level)
+	}
+}
+
+// ensureContrast returns fg, re-toned towards black or white as needed to reach the given
+// contrast ratio against bg. Mid-tone backgrounds may not allow high ratios at all, in
+// which case the better of black and white is returned.
+func ensureContrast(fg, bg color.NRGBA, ratio float64) color.NRGBA {
+	if ContrastRatio(fg, bg) >= ratio {
+		return fg
+	}
+	palette := TonalPaletteFromColor(fg)
+	bgTone := colorTone(bg)
+	// Go towards whichever extreme can reach the ratio, preferring the side fg is on
+	step := 1.0
+	if colorTone(fg) < bgTone {
+		step = -1
+	}
+	bgY := relativeLuminance(bg)
+	if contrastY(toneToY(50+step*50), bgY) < ratio {
+		if contrastY(toneToY(50-step*50), bgY) < ratio {
+			// Neither black nor white reaches the ratio, so use the better of the two
+			if contrastY(0, bgY) > contrastY(1, bgY) {
+				return color.NRGBA{A: 255}
+			}
+			return color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		step = -step
+	}
+	for tone := colorTone(fg); tone >= 0 && tone <= 100; tone += step {
+		c := palette.Tone(tone)
+		if ContrastRatio(c, bg) >= ratio {
+			return c
+		}
+	}
+	return palette.Tone(50 + step*50)
+}
+
+// ContrastRatio returns the WCAG 2.x contrast ratio between two colors (1 to 21)
+func ContrastRatio(a, b color.NRGBA) float64 {
+	return contrastY(relativeLuminance(a), relativeLuminance(b))
+}
+
+// contrastY returns the WCAG contrast ratio between two relative luminances
+func contrastY(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	return (a + 0.05) / (b + 0.05)
+}
+
+// APCAContrast returns the APCA (0.0.98G-4g) lightness contrast Lc of text on a background.
+// Positive values are dark text on a light background, negative values light on dark;
+// |Lc| 60 is roughly equivalent to WCAG 4.5:1 for body text, 75 to 7:1.
+func APCAContrast(text, background color.NRGBA) float64 {
+	const (
+		blackThreshold = 0.022
+		blackClamp     = 1.414
+		scale          = 1.14
+		offset         = 0.027
+		clip           = 0.1
+		deltaYMin      = 0.0005
+	)
+	y := func(c color.NRGBA) float64 {
+		lin := func(v uint8) float64 { return math.Pow(float64(v)/255, 2.4) }
+		y := 0.2126729*lin(c.R) + 0.7151522*lin(c.G) + 0.0721750*lin(c.B)
+		if y < blackThreshold {
+			y += math.Pow(blackThreshold-y, blackClamp)
+		}
+		return y
+	}
+	yText, yBg := y(text), y(background)
+	if math.Abs(yBg-yText) < deltaYMin {
+		return 0
+	}
+	if yBg > yText {
+		// Dark text on a light background
+		sapc := (math.Pow(yBg, 0.56) - math.Pow(yText, 0.57)) * scale
+		if sapc < clip {
+			return 0
+		}
+		return (sapc - offset) * 100
+	}
+	// Light text on a dark background
+	sapc := (math.Pow(yBg, 0.65) - math.Pow(yText, 0.62)) * scale
+	if sapc > -clip {
+		return 0
+	}
+	return (sapc + offset) * 100
+}
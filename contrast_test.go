@@ -0,0 +1,88 @@
+package fromage
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	black := color.NRGBA{A: 255}
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	if r := ContrastRatio(black, white); math.Abs(r-21) > 0.01 {
+		t.Errorf("Expected black on white to be 21:1, got %.2f", r)
+	}
+	if r := ContrastRatio(white, white); r != 1 {
+		t.Errorf("Expected white on white to be 1:1, got %.2f", r)
+	}
+}
+
+func TestEnsureContrast(t *testing.T) {
+	bg := rgb(0x777777)
+	fg := ensureContrast(rgb(0x888888), bg, 4.5)
+	if r := ContrastRatio(fg, bg); r < 4.5 {
+		t.Errorf("Expected adjusted contrast of at least 4.5, got %.2f", r)
+	}
+}
+
+func TestAPCAContrast(t *testing.T) {
+	black := color.NRGBA{A: 255}
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	// Reference values from the APCA 0.0.98G-4g calculator
+	if lc := APCAContrast(black, white); math.Abs(lc-106.04) > 0.1 {
+		t.Errorf("Expected black on white Lc 106.04, got %.2f", lc)
+	}
+	if lc := APCAContrast(white, black); math.Abs(lc+107.88) > 0.1 {
+		t.Errorf("Expected white on black Lc -107.88, got %.2f", lc)
+	}
+	if lc := APCAContrast(white, white); lc != 0 {
+		t.Errorf("Expected white on white Lc 0, got %.2f", lc)
+	}
+}
+
+func TestAuditContrast(t *testing.T) {
+	colors := NewColorsWithMode(ThemeModeDark)
+	// A light surface tint in dark mode makes OnSurface unreadable
+	colors.SetSurfaceTint(rgb(0xE0E0E0))
+
+	report := colors.AuditContrast(ContrastAA)
+	if len(report) != len(rolePairs) {
+		t.Fatalf("Expected %d results, got %d", len(rolePairs), len(report))
+	}
+	var surface *ContrastResult
+	for i := range report.Failures() {
+		if report.Failures()[i].Background == "Surface" {
+			surface = &report.Failures()[i]
+		}
+	}
+	if surface == nil {
+		t.Fatal("Expected Surface/OnSurface to fail AA")
+	}
+	if surface.SuggestedRatio < float64(ContrastAA) {
+		t.Errorf("Expected suggested OnSurface to reach AA, got %.2f", surface.SuggestedRatio)
+	}
+	if report.OK() {
+		t.Error("Expected report with failures not to be OK")
+	}
+}
+
+func TestSetMinContrast(t *testing.T) {
+	colors := NewColorsWithMode(ThemeModeDark).SetMinContrast(ContrastAA)
+	colors.SetSurfaceTint(rgb(0xE0E0E0))
+	for _, f := range colors.AuditContrast(ContrastAA).Failures() {
+		t.Errorf("%s on %s still at %.2f", f.Foreground, f.Background, f.Ratio)
+	}
+	colors.SetThemeMode(ThemeModeLight)
+	if !colors.AuditContrast(ContrastAA).OK() {
+		t.Error("Expected contrast to be corrected again after a mode change")
+	}
+}
+
+func TestEnsureContrastUnreachable(t *testing.T) {
+	// No color reaches 7:1 on a mid grey, so the best extreme is used
+	bg := rgb(0x777777)
+	fg := ensureContrast(rgb(0x888888), bg, 7)
+	if fg != (color.NRGBA{A: 255}) {
+		t.Errorf("Expected black as the best available foreground, got %v", fg)
+	}
+}
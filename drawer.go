@@ -37,6 +37,7 @@ type Drawer struct {
 	isAnimating       bool      // Whether animation is in progress
 	animationStarted  bool      // Whether animation has ever been started
 	isFadingOut       bool      // Whether we're fading out (true) or fading in (false)
+	fadeOutPending    bool      // Whether the next frame starts the fade-out
 	isVisible         bool      // Whether the drawer should be visible
 }
 
@@ -99,7 +100,8 @@ func (d *Drawer) Show() {
 		return
 	}
 	d.isVisible = true
-	d.startAnimation(time.Now())
+	// Layout starts the animation at the frame time
+	d.animationStarted = false
 }
 
 // Hide makes the drawer invisible with animation
@@ -283,13 +285,14 @@ func (d *Drawer) startAnimation(now time.Time) {
 	d.isAnimating = true
 	d.animationStarted = true
 	d.isFadingOut = false
+	d.fadeOutPending = false
 }
 
 // startFadeOut begins the slide-out animation
 func (d *Drawer) startFadeOut() {
-	d.animationStart = time.Now()
 	d.isAnimating = true
 	d.isFadingOut = true
+	d.fadeOutPending = true
 }
 
 // updateAnimation updates the animation progress
@@ -298,10 +301,14 @@ func (d *Drawer) updateAnimation(g C) {
 		return
 	}
 
-	const animationDuration = 300 * time.Millisecond
-	elapsed := g.Now.Sub(d.animationStart)
+	if d.fadeOutPending {
+		d.animationStart = g.Now
+		d.fadeOutPending = false
+	}
+	motion := d.Theme.motion()
+	easedProgress, done := motion.Progress(d.animationStart, g.Now, motion.Long, motion.Emphasized)
 
-	if elapsed >= animationDuration {
+	if done {
 		if d.isFadingOut {
 			d.animationProgress = 0.0
 			d.isAnimating = false
@@ -312,14 +319,6 @@ func (d *Drawer) updateAnimation(g C) {
 		return
 	}
 
-	progress := float32(elapsed) / float32(animationDuration)
-	if progress > 1.0 {
-		progress = 1.0
-	}
-
-	// Use ease-out curve: 1 - (1-t)^3 for smoother animation
-	easedProgress := 1.0 - (1.0-progress)*(1.0-progress)*(1.0-progress)
-
 	if d.isFadingOut {
 		d.animationProgress = 1.0 - easedProgress
 	} else {
@@ -55,7 +55,8 @@ func (w *Window) NewDrawerWithControls() *DrawerWithControls {
 			// Update position after a short delay to allow hide animation
 			go func() {
 				// Wait for hide animation to complete
-				time.Sleep(350 * time.Millisecond)
+				motion := dwc.Theme.motion()
+				time.Sleep(motion.Duration(motion.Long) + 50*time.Millisecond)
 
 				// Update position and show with animation
 				dwc.currentPos = newPos
@@ -0,0 +1,250 @@
+package fromage
+
+import (
+	"image"
+	"strings"
+	"time"
+	"unicode"
+
+	"gio.mleku.dev/f32"
+	"gio.mleku.dev/io/event"
+	"gio.mleku.dev/io/input"
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/io/pointer"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/unit"
+)
+
+// Driver lays out a widget frame by frame without a window and feeds it synthetic input,
+// for tests of widget behavior. Input is queued on an event router and delivered to the
+// handlers registered in the last frame, so every input helper lays out a frame
+// afterwards for the widget to react. Time only passes when the driver advances its
+// clock, which makes animations deterministic.
+type Driver struct {
+	// Theme reference
+	theme *Theme
+	// Widget under test
+	widget W
+	// Router delivering the queued events
+	router input.Router
+	ops    op.Ops
+	// Size of the frame in pixels, and its density
+	size   image.Point
+	metric unit.Metric
+	// Fake clock, and its starting time for event timestamps
+	now, start time.Time
+	// Last pointer position
+	pointer f32.Point
+	// Dimensions returned by the last layout
+	dims D
+}
+
+// driverEpoch is the starting time of the driver's clock, so animations and transitions
+// run the same every time
+var driverEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// driverFrameInterval is the time between frames in Run, for a 60 Hz display
+const driverFrameInterval = time.Second / 60
+
+// driverDragSteps is the number of moves a Drag makes between its ends
+const driverDragSteps = 8
+
+// NewDriver creates a driver laying out w with exact constraints of size pixels at one
+// pixel per dp. It lays out the first frame so the widget is ready for input.
+func (t *Theme) NewDriver(size image.Point, w W) *Driver {
+	d := &Driver{theme: t, widget: w, size: size, metric: unit.Metric{PxPerDp: 1, PxPerSp: 1},
+		now: driverEpoch, start: driverEpoch}
+	d.Frame()
+	return d
+}
+
+// Scale sets the pixels per dp and sp and lays out a frame
+func (d *Driver) Scale(pxPerDp float32) *Driver {
+	d.metric = unit.Metric{PxPerDp: pxPerDp, PxPerSp: pxPerDp}
+	d.Frame()
+	return d
+}
+
+// Now returns the driver's clock
+func (d *Driver) Now() time.Time { return d.now }
+
+// Dimensions returns the dimensions of the last layout
+func (d *Driver) Dimensions() D { return d.dims }
+
+// Ops returns the operations of the last frame
+func (d *Driver) Ops() *op.Ops { return &d.ops }
+
+// Focused reports whether tag has the keyboard focus
+func (d *Driver) Focused(tag event.Tag) bool {
+	return d.router.Source().Focused(tag)
+}
+
+// Clipboard returns the text the widget last wrote to the clipboard, and whether it
+// wrote any since the previous call
+func (d *Driver) Clipboard() (string, bool) {
+	_, content, ok := d.router.WriteClipboard()
+	return string(content), ok
+}
+
+// Frame lays out the widget at the current time, delivering queued input
+func (d *Driver) Frame() D {
+	d.ops.Reset()
+	gtx := layout.Context{
+		Ops:         &d.ops,
+		Metric:      d.metric,
+		Constraints: layout.Exact(d.size),
+		Now:         d.now,
+		Source:      d.router.Source(),
+	}
+	d.theme.BeginFrame(gtx)
+	paint.Fill(gtx.Ops, d.theme.Colors.Background())
+	d.dims = d.widget(gtx)
+	d.router.Frame(&d.ops)
+	return d.dims
+}
+
+// Advance moves the clock forward and lays out a frame
+func (d *Driver) Advance(dt time.Duration) D {
+	d.now = d.now.Add(dt)
+	return d.Frame()
+}
+
+// Run lays out frames about 60 times a second until dt has passed, letting animations
+// finish. The last frame is exactly dt from now.
+func (d *Driver) Run(dt time.Duration) D {
+	start := d.now
+	frames := max(int((dt+driverFrameInterval/2)/driverFrameInterval), 1)
+	for i := 1; i < frames; i++ {
+		d.now = start.Add(dt * time.Duration(i) / time.Duration(frames))
+		d.Frame()
+	}
+	d.now = start.Add(dt)
+	return d.Frame()
+}
+
+// Queue delivers events in the next frame without laying it out, for sequences the
+// helpers do not cover
+func (d *Driver) Queue(events ...event.Event) {
+	d.router.Queue(events...)
+}
+
+// Move moves the pointer to at and lays out a frame
+func (d *Driver) Move(at image.Point) D {
+	d.queuePointer(pointer.Move, at, 0)
+	return d.Frame()
+}
+
+// Click moves to at and clicks the primary button. The clock does not move between
+// clicks, so clicking twice or three times at the same place is a double or triple click.
+func (d *Driver) Click(at image.Point) D {
+	return d.clickButton(at, pointer.ButtonPrimary)
+}
+
+// RightClick moves to at and clicks the secondary button
+func (d *Driver) RightClick(at image.Point) D {
+	return d.clickButton(at, pointer.ButtonSecondary)
+}
+
+// Drag presses the primary button at from, moves to to in steps, one frame each, and
+// releases it there
+func (d *Driver) Drag(from, to image.Point) D {
+	d.queuePointer(pointer.Move, from, 0)
+	d.queuePointer(pointer.Press, from, pointer.ButtonPrimary)
+	d.Frame()
+	for i := 1; i <= driverDragSteps; i++ {
+		at := from.Add(to.Sub(from).Mul(i).Div(driverDragSteps))
+		// The router reports moves with a button held as drags
+		d.queuePointer(pointer.Move, at, pointer.ButtonPrimary)
+		d.Frame()
+	}
+	d.queuePointer(pointer.Release, to, 0)
+	return d.Frame()
+}
+
+// Scroll scrolls by delta pixels with the pointer at at; positive values scroll down and
+// right
+func (d *Driver) Scroll(at, delta image.Point) D {
+	d.queuePointer(pointer.Move, at, 0)
+	d.router.Queue(pointer.Event{
+		Kind:     pointer.Scroll,
+		Source:   pointer.Mouse,
+		Position: d.pointer,
+		Scroll:   f32.Pt(float32(delta.X), float32(delta.Y)),
+		Time:     d.now.Sub(d.start),
+	})
+	return d.Frame()
+}
+
+// Focus gives the keyboard focus to tag and lays out a frame
+func (d *Driver) Focus(tag event.Tag) D {
+	d.router.Source().Execute(key.FocusCmd{Tag: tag})
+	return d.Frame()
+}
+
+// Press presses and releases a key with the modifiers held, such as
+// Press(key.NameEscape) or Press("C", key.ModShortcut)
+func (d *Driver) Press(name key.Name, modifiers ...key.Modifiers) D {
+	var mods key.Modifiers
+	for _, m := range modifiers {
+		mods |= m
+	}
+	d.queueKey(name, mods)
+	return d.Frame()
+}
+
+// Type presses and releases the key of each character of text in turn, with shift for
+// capital letters. It sends key events, as shortcuts and key filters see them; text
+// input widgets that read key.EditEvent need those queued with the selection to replace.
+func (d *Driver) Type(text string) D {
+	for _, r := range text {
+		name, mods := runeKey(r)
+		d.queueKey(name, mods)
+	}
+	return d.Frame()
+}
+
+// clickButton moves to at and presses and releases a button there
+func (d *Driver) clickButton(at image.Point, button pointer.Buttons) D {
+	d.queuePointer(pointer.Move, at, 0)
+	d.queuePointer(pointer.Press, at, button)
+	d.queuePointer(pointer.Release, at, 0)
+	return d.Frame()
+}
+
+// queuePointer queues a mouse event at at with the buttons held
+func (d *Driver) queuePointer(kind pointer.Kind, at image.Point, buttons pointer.Buttons) {
+	d.pointer = f32.Pt(float32(at.X), float32(at.Y))
+	d.router.Queue(pointer.Event{
+		Kind:     kind,
+		Source:   pointer.Mouse,
+		Buttons:  buttons,
+		Position: d.pointer,
+		Time:     d.now.Sub(d.start),
+	})
+}
+
+// queueKey queues a press and a release of a key
+func (d *Driver) queueKey(name key.Name, mods key.Modifiers) {
+	d.router.Queue(
+		key.Event{Name: name, Modifiers: mods, State: key.Press},
+		key.Event{Name: name, Modifiers: mods, State: key.Release},
+	)
+}
+
+// runeKey returns the key that types r, with shift for capital letters
+func runeKey(r rune) (key.Name, key.Modifiers) {
+	switch r {
+	case ' ':
+		return key.NameSpace, 0
+	case '\n':
+		return key.NameReturn, 0
+	case '\t':
+		return key.NameTab, 0
+	}
+	if unicode.IsUpper(r) {
+		return key.Name(string(r)), key.ModShift
+	}
+	return key.Name(strings.ToUpper(string(r))), 0
+}
@@ -0,0 +1,177 @@
+package fromage
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/op/clip"
+)
+
+func TestDriverClock(t *testing.T) {
+	th := newGoldenTheme()
+	on := NewValue(false)
+	sw := th.Switch(false).Bind(on)
+	d := th.NewDriver(image.Pt(100, 40), sw.Layout)
+	start := d.Now()
+
+	on.Set(true)
+	d.Frame()
+	d.Advance(th.motion().Medium / 2)
+	if p := sw.animationProgress; p <= 0 || p >= 1 {
+		t.Errorf("Expected the switch halfway through its animation, got %v", p)
+	}
+	d.Run(time.Second)
+	if sw.animationProgress != 1 || sw.isAnimating {
+		t.Errorf("Expected the animation to finish, got %v", sw.animationProgress)
+	}
+	if got := d.Now().Sub(start); got != th.motion().Medium/2+time.Second {
+		t.Errorf("Expected the clock to advance only when driven, got %v", got)
+	}
+}
+
+func TestDriverRun(t *testing.T) {
+	th := newGoldenTheme()
+	frames := 0
+	var times []time.Time
+	d := th.NewDriver(image.Pt(10, 10), func(g C) D {
+		frames++
+		times = append(times, g.Now)
+		return D{Size: g.Constraints.Max}
+	})
+	d.Run(100 * time.Millisecond)
+	if frames != 7 {
+		t.Errorf("Expected the first frame and six at 60 Hz, got %d", frames)
+	}
+	if last := times[len(times)-1]; last.Sub(times[0]) != 100*time.Millisecond {
+		t.Errorf("Expected the last frame at 100ms, got %v", last.Sub(times[0]))
+	}
+	if d.Dimensions().Size != image.Pt(10, 10) {
+		t.Errorf("Expected the exact frame size, got %v", d.Dimensions().Size)
+	}
+}
+
+func TestRuneKey(t *testing.T) {
+	for r, want := range map[rune]struct {
+		name key.Name
+		mods key.Modifiers
+	}{
+		'a':  {"A", 0},
+		'A':  {"A", key.ModShift},
+		'7':  {"7", 0},
+		' ':  {key.NameSpace, 0},
+		'\n': {key.NameReturn, 0},
+	} {
+		if name, mods := runeKey(r); name != want.name || mods != want.mods {
+			t.Errorf("Expected %q to be %q with %v, got %q with %v", r, want.name, want.mods, name, mods)
+		}
+	}
+}
+
+func TestDriverButtonClick(t *testing.T) {
+	th := newGoldenTheme()
+	clicks := 0
+	b := th.NewButtonLayout().OnClick(func() { clicks++ }).Widget(func(g C) D {
+		return D{Size: image.Pt(80, 30)}
+	})
+	d := th.NewDriver(image.Pt(100, 40), func(g C) D {
+		// The button registers its handler unclipped, so bound it here.
+		defer clip.Rect{Max: image.Pt(80, 30)}.Push(g.Ops).Pop()
+		g.Constraints.Min = image.Point{}
+		return b.Layout(g)
+	})
+	d.Frame()
+	d.Click(image.Pt(40, 15))
+	if clicks != 1 {
+		t.Errorf("Expected one click, got %d", clicks)
+	}
+	d.Click(image.Pt(95, 38))
+	if clicks != 1 {
+		t.Errorf("Expected a click outside the button to be ignored, got %d clicks", clicks)
+	}
+}
+
+func TestDriverSliderDrag(t *testing.T) {
+	th := newGoldenTheme()
+	count := NewValue(0)
+	amount := NewValue(float32(0))
+	i := th.NewInt().SetRange(0, 10).Bind(count)
+	f := th.NewFloat().Bind(amount)
+	d := th.NewDriver(image.Pt(200, 80), func(g C) D {
+		return th.VFlex().
+			Rigid(func(g C) D { return i.Layout(g, th) }).
+			Rigid(func(g C) D { return f.Layout(g, th) }).
+			Layout(g)
+	})
+	d.Frame()
+	d.Drag(image.Pt(10, 20), image.Pt(100, 20))
+	if got := count.Get(); got != 5 {
+		t.Errorf("Expected the int slider at 5, got %d", got)
+	}
+	d.Drag(image.Pt(10, 60), image.Pt(150, 60))
+	if got := amount.Get(); got < 0.74 || got > 0.76 {
+		t.Errorf("Expected the float slider at 0.75, got %v", got)
+	}
+	if got := count.Get(); got != 5 {
+		t.Errorf("Expected dragging the float slider to leave the int slider, got %d", got)
+	}
+}
+
+func TestDriverScrollbarTrackClick(t *testing.T) {
+	th := newGoldenTheme()
+	var moved []float32
+	s := th.NewScrollbar(Horizontal).SetViewport(0.25).SetHook(func(p float32) {
+		moved = append(moved, p)
+	})
+	d := th.NewDriver(image.Pt(200, 20), func(g C) D { return s.Layout(g, th) })
+	d.Frame()
+	d.Click(image.Pt(150, 8))
+	if s.Position() != 0 {
+		t.Errorf("Expected the track click to animate rather than jump, got %v", s.Position())
+	}
+	d.Run(th.motion().Medium)
+	if got := s.Position(); got < 0.24 || got > 0.26 {
+		t.Errorf("Expected a track click to page by the thumb, got %v", got)
+	}
+	if len(moved) == 0 {
+		t.Error("Expected the hook to report the new position")
+	}
+	d.Run(time.Second)
+	if got := s.Position(); got > 0.26 {
+		t.Errorf("Expected a short click not to scroll to the end, got %v", got)
+	}
+}
+
+func TestDriverGlobalMenuRightClick(t *testing.T) {
+	th := newGoldenTheme()
+	gm := th.NewGlobalMenu().AddItem("First", nil).AddItem("Second", nil)
+	d := th.NewDriver(image.Pt(400, 300), func(g C) D {
+		gm.Layout(g)
+		return D{Size: g.Constraints.Max}
+	})
+	d.Frame()
+	if gm.IsVisible() {
+		t.Fatal("Expected the menu to start hidden")
+	}
+	d.RightClick(image.Pt(50, 60))
+	if !gm.IsVisible() {
+		t.Fatal("Expected a right-click to open the menu")
+	}
+	if got := gm.GetClickPosition(); got != image.Pt(50, 60) {
+		t.Errorf("Expected the menu at the click, got %v", got)
+	}
+	if !gm.animating {
+		t.Error("Expected the menu to fade in")
+	}
+	d.Run(th.motion().Medium)
+	if gm.animating {
+		t.Error("Expected the fade-in to finish on the driver clock")
+	}
+	gm.Hide()
+	d.Frame()
+	d.Run(th.motion().Medium)
+	if gm.IsVisible() {
+		t.Error("Expected the menu to close after fading out")
+	}
+}
@@ -0,0 +1,39 @@
+package fromage
+
+import (
+	"gio.mleku.dev/widget"
+)
+
+// BoundEditor keeps a Gio text editor and an observable string in step, so editors can be
+// bound to a Value like the other input widgets. The editor is laid out as usual; calling
+// Sync before that each frame writes what the user typed to the value and shows changes
+// made to the value elsewhere.
+type BoundEditor struct {
+	// Editor is the bound text editor
+	*widget.Editor
+	// Observable text the editor shows and edits
+	bound binding[string]
+	// Text of the editor when it was last synchronized
+	synced string
+}
+
+// BindEditor binds a text editor to an observable string
+func (t *Theme) BindEditor(e *widget.Editor, v *Value[string]) *BoundEditor {
+	b := &BoundEditor{Editor: e, synced: e.Text()}
+	b.bound.bind(v)
+	v.Invalidates(t)
+	return b
+}
+
+// Sync writes the user's edits since the last call to the value, then shows the value if
+// it was changed elsewhere
+func (b *BoundEditor) Sync() {
+	if text := b.Editor.Text(); text != b.synced {
+		b.synced = text
+		b.bound.push(text)
+	}
+	if v, ok := b.bound.pull(); ok && v != b.synced {
+		b.Editor.SetText(v)
+		b.synced = v
+	}
+}
@@ -0,0 +1,130 @@
+package fromage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"image/color"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// paletteStepRe splits a palette field name such as "NeutralVariant500" into group and step
+var paletteStepRe = regexp.MustCompile(`^([A-Za-z]+)(\d+)$`)
+
+// exportMode pairs a theme mode name with its roles for the exporters
+type exportMode struct {
+	name  string
+	roles ColorRoles
+}
+
+// exportModes returns the target roles of both light and dark mode
+func (t *Colors) exportModes() []exportMode {
+	return []exportMode{
+		{"light", t.RolesFor(ThemeModeLight)},
+		{"dark", t.RolesFor(ThemeModeDark)},
+	}
+}
+
+// ExportCSS writes the palette and the roles of both modes as CSS custom properties.
+// Light roles are the default; dark roles apply with prefers-color-scheme: dark or
+// data-theme="dark" on the root element, and data-theme="light" forces light.
+func (t *Colors) ExportCSS(w io.Writer) error {
+	var b strings.Builder
+	modes := t.exportModes()
+	writeRoles := func(indent string, roles ColorRoles) {
+		roles.each(func(name string, c *color.NRGBA) {
+			fmt.Fprintf(&b, "%s--md-sys-color-%s: %s;\n", indent, kebab(name), ColorToHex(*c))
+		})
+	}
+	b.WriteString(":root {\n")
+	palette := t.palette
+	palette.each(func(name string, c *color.NRGBA) {
+		m := paletteStepRe.FindStringSubmatch(name)
+		fmt.Fprintf(&b, "  --md-ref-palette-%s-%s: %s;\n", kebab(m[1]), m[2], ColorToHex(*c))
+	})
+	writeRoles("  ", modes[0].roles)
+	b.WriteString("}\n\n")
+	b.WriteString("@media (prefers-color-scheme: dark) {\n  :root:not([data-theme=\"light\"]) {\n")
+	writeRoles("    ", modes[1].roles)
+	b.WriteString("  }\n}\n\n")
+	b.WriteString(":root[data-theme=\"dark\"] {\n")
+	writeRoles("  ", modes[1].roles)
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// designToken is a single W3C design tokens color value
+type designToken struct {
+	Type  string `json:"$type"`
+	Value string `json:"$value"`
+}
+
+// ExportDesignTokens writes the palette and the roles of both modes in the W3C design
+// tokens format, grouped as palette.<group>.<step> and color.<mode>.<role>
+func (t *Colors) ExportDesignTokens(w io.Writer) error {
+	palette := make(map[string]map[string]designToken)
+	p := t.palette
+	p.each(func(name string, c *color.NRGBA) {
+		m := paletteStepRe.FindStringSubmatch(name)
+		group := themeFileKey(m[1])
+		if palette[group] == nil {
+			palette[group] = make(map[string]designToken)
+		}
+		palette[group][m[2]] = designToken{Type: "color", Value: ColorToHex(*c)}
+	})
+	colors := make(map[string]map[string]designToken)
+	for _, mode := range t.exportModes() {
+		colors[mode.name] = make(map[string]designToken)
+		mode.roles.each(func(name string, c *color.NRGBA) {
+			colors[mode.name][themeFileKey(name)] = designToken{Type: "color", Value: ColorToHex(*c)}
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{"palette": palette, "color": colors})
+}
+
+// ExportGo writes a gofmt-formatted Go source file for package pkg declaring the palette and
+// the roles of both modes as 0xRRGGBB constants, e.g. Primary500, LightOnPrimary, DarkSurface
+func (t *Colors) ExportGo(w io.Writer, pkg string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by fromage; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	constant := func(name string, c color.NRGBA) {
+		fmt.Fprintf(&b, "\t%s = 0x%02X%02X%02X\n", name, c.R, c.G, c.B)
+	}
+	b.WriteString("// Palette colors\nconst (\n")
+	palette := t.palette
+	palette.each(func(name string, c *color.NRGBA) { constant(name, *c) })
+	b.WriteString(")\n")
+	for _, mode := range t.exportModes() {
+		prefix := strings.ToUpper(mode.name[:1]) + mode.name[1:]
+		fmt.Fprintf(&b, "\n// %s theme role colors\nconst (\n", prefix)
+		mode.roles.each(func(name string, c *color.NRGBA) { constant(prefix+name, *c) })
+		b.WriteString(")\n")
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// kebab converts a Go field name such as "OnPrimaryContainer" to "on-primary-container"
+func kebab(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package fromage
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestExportCSS(t *testing.T) {
+	colors := NewColorsFromSeed(0x6750A4)
+	var buf bytes.Buffer
+	if err := colors.ExportCSS(&buf); err != nil {
+		t.Fatalf("ExportCSS failed: %v", err)
+	}
+	css := buf.String()
+	light, dark := colors.RolesFor(ThemeModeLight), colors.RolesFor(ThemeModeDark)
+	for _, want := range []string{
+		"--md-ref-palette-neutral-variant-500: " + ColorToHex(colors.Palette().NeutralVariant500) + ";",
+		"--md-sys-color-on-primary-container: " + ColorToHex(light.OnPrimaryContainer) + ";",
+		"--md-sys-color-on-primary-container: " + ColorToHex(dark.OnPrimaryContainer) + ";",
+		"@media (prefers-color-scheme: dark)",
+		":root[data-theme=\"dark\"]",
+	} {
+		if !strings.Contains(css, want) {
+			t.Errorf("Expected CSS to contain %q", want)
+		}
+	}
+}
+
+func TestExportDesignTokens(t *testing.T) {
+	colors := NewColorsWithMode(ThemeModeDark)
+	var buf bytes.Buffer
+	if err := colors.ExportDesignTokens(&buf); err != nil {
+		t.Fatalf("ExportDesignTokens failed: %v", err)
+	}
+	var tokens struct {
+		Palette map[string]map[string]designToken `json:"palette"`
+		Color   map[string]map[string]designToken `json:"color"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &tokens); err != nil {
+		t.Fatalf("Invalid tokens JSON: %v", err)
+	}
+	if got := tokens.Palette["neutralVariant"]["950"]; got.Type != "color" || got.Value != ColorToHex(colors.Palette().NeutralVariant950) {
+		t.Errorf("Unexpected palette token %+v", got)
+	}
+	if got := tokens.Color["light"]["primary"].Value; got != ColorToHex(colors.RolesFor(ThemeModeLight).Primary) {
+		t.Errorf("Unexpected light primary token %s", got)
+	}
+	if got := tokens.Color["dark"]["surface"].Value; got != ColorToHex(colors.Surface()) {
+		t.Errorf("Unexpected dark surface token %s", got)
+	}
+}
+
+func TestExportGo(t *testing.T) {
+	colors := NewColors()
+	var buf bytes.Buffer
+	if err := colors.ExportGo(&buf, "brand"); err != nil {
+		t.Fatalf("ExportGo failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "brand.go", buf.Bytes(), 0); err != nil {
+		t.Fatalf("Generated Go does not parse: %v", err)
+	}
+	src := buf.String()
+	primary := colors.Primary()
+	want := "LightPrimary"
+	if !strings.Contains(src, want) || !strings.Contains(src, strings.TrimPrefix(ColorToHex(primary), "#")) {
+		t.Errorf("Expected generated source to declare %s = 0x%s", want, ColorToHex(primary)[1:])
+	}
+	if !strings.Contains(src, "package brand") || !strings.Contains(src, "DarkOnSurface") {
+		t.Error("Expected package clause and dark role constants")
+	}
+}
+
+func TestKebab(t *testing.T) {
+	if got := kebab("OnPrimaryContainer"); got != "on-primary-container" {
+		t.Errorf("Expected on-primary-container, got %s", got)
+	}
+}
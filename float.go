@@ -14,6 +14,7 @@ import (
 
 // Float is a slider widget for selecting float values
 type Float struct {
+	theme      *Theme
 	value      float32
 	clickable  *widget.Clickable
 	pos        float32 // position normalized to [0, 1]
@@ -23,11 +24,13 @@ type Float struct {
 	min, max   float32
 	dragging   bool
 	drag       gesture.Drag
+	bound      binding[float32] // observable value the slider shows and edits
 }
 
 // NewFloat creates a new float slider
 func (t *Theme) NewFloat() *Float {
 	return &Float{
+		theme:      t,
 		clickable:  t.Pool.GetClickable(),
 		changeHook: func(float32) {},
 		min:        0,
@@ -46,6 +49,13 @@ func (f *Float) Value() float32 {
 	return f.value
 }
 
+// Bind makes the slider show and edit an observable value
+func (f *Float) Bind(v *Value[float32]) *Float {
+	f.bound.bind(v)
+	v.Invalidates(f.theme)
+	return f
+}
+
 // SetRange sets the min and max values
 func (f *Float) SetRange(min, max float32) *Float {
 	f.min = min
@@ -73,6 +83,11 @@ func (f *Float) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 	size := gtx.Constraints.Min
 	f.length = float32(size.X)
 
+	// Follow changes to the bound value
+	if value, ok := f.bound.pull(); ok {
+		f.value = value
+	}
+
 	// Update position based on current value
 	if f.min != f.max {
 		f.pos = (f.value - f.min) / (f.max - f.min)
@@ -104,6 +119,7 @@ func (f *Float) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 			f.pos = newPos
 			f.value = f.min + f.pos*(f.max-f.min)
 			f.changed = true
+			f.bound.push(f.value)
 			f.changeHook(f.value)
 		case pointer.Drag:
 			if f.dragging {
@@ -117,6 +133,7 @@ func (f *Float) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 				f.pos = newPos
 				f.value = f.min + f.pos*(f.max-f.min)
 				f.changed = true
+				f.bound.push(f.value)
 				f.changeHook(f.value)
 			}
 		case pointer.Release:
@@ -0,0 +1,202 @@
+package fromage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/font/opentype"
+	"gio.mleku.dev/text"
+)
+
+// FontLibrary collects font faces and fallback chains and builds text shapers from them
+type FontLibrary struct {
+	mx sync.Mutex
+	// faces are all loaded font faces
+	faces []font.FontFace
+	// fallbacks maps a typeface to the typefaces tried for runes it does not cover
+	fallbacks map[font.Typeface][]font.Typeface
+	// systemFonts controls whether the shaper may also use the platform's installed fonts
+	systemFonts bool
+}
+
+// NewFontLibrary creates an empty font library that also uses system fonts
+func NewFontLibrary() *FontLibrary {
+	return &FontLibrary{
+		fallbacks:   make(map[font.Typeface][]font.Typeface),
+		systemFonts: true,
+	}
+}
+
+// AddCollection adds already parsed faces, such as gofont.Collection()
+func (l *FontLibrary) AddCollection(faces []font.FontFace) *FontLibrary {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.faces = append(l.faces, faces...)
+	return l
+}
+
+// LoadBytes parses a TTF, OTF or TTC font and adds every face in it, using the family,
+// weight and style recorded in the font file
+func (l *FontLibrary) LoadBytes(name string, data []byte) error {
+	faces, err := opentype.ParseCollection(data)
+	if err != nil {
+		return fmt.Errorf("parsing font %s: %w", name, err)
+	}
+	l.AddCollection(faces)
+	return nil
+}
+
+// LoadFile loads a TTF, OTF or TTC font file
+func (l *FontLibrary) LoadFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return l.LoadBytes(filename, data)
+}
+
+// LoadFS loads every .ttf, .otf and .ttc file matching pattern in fsys, e.g. an embed.FS
+// with the pattern "fonts/*"
+func (l *FontLibrary) LoadFS(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		switch strings.ToLower(path.Ext(name)) {
+		case ".ttf", ".otf", ".ttc":
+		default:
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err = l.LoadBytes(name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register parses a single-face TTF or OTF font and adds it under the given family, weight
+// and style, overriding the names recorded in the font file
+func (l *FontLibrary) Register(family font.Typeface, weight font.Weight, style font.Style, data []byte) error {
+	face, err := opentype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("parsing font for %s: %w", family, err)
+	}
+	l.AddCollection([]font.FontFace{{
+		Font: font.Font{Typeface: family, Weight: weight, Style: style},
+		Face: face,
+	}})
+	return nil
+}
+
+// Fallback sets the typefaces tried, in order, for runes the family does not cover, such as
+// CJK, emoji or symbol fonts. The empty typeface sets the chain for unstyled text.
+func (l *FontLibrary) Fallback(family font.Typeface, chain ...font.Typeface) *FontLibrary {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.fallbacks[family] = chain
+	return l
+}
+
+// SystemFonts sets whether the shaper may also use the platform's installed fonts
+func (l *FontLibrary) SystemFonts(enabled bool) *FontLibrary {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.systemFonts = enabled
+	return l
+}
+
+// Faces returns a copy of the loaded faces
+func (l *FontLibrary) Faces() []font.FontFace {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	return append([]font.FontFace(nil), l.faces...)
+}
+
+// Resolve returns the font with its typeface expanded to the comma-separated fallback list
+// understood by the shaper
+func (l *FontLibrary) Resolve(f font.Font) font.Font {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	chain, ok := l.fallbacks[f.Typeface]
+	if !ok || len(chain) == 0 {
+		return f
+	}
+	names := make([]string, 0, len(chain)+1)
+	if f.Typeface != "" {
+		names = append(names, string(f.Typeface))
+	}
+	for _, fb := range chain {
+		names = append(names, string(fb))
+	}
+	f.Typeface = font.Typeface(strings.Join(names, ", "))
+	return f
+}
+
+// NewShaper builds a text shaper from the loaded faces
+func (l *FontLibrary) NewShaper() *text.Shaper {
+	l.mx.Lock()
+	opts := []text.ShaperOption{text.WithCollection(append([]font.FontFace(nil), l.faces...))}
+	if !l.systemFonts {
+		opts = append(opts, text.NoSystemFonts())
+	}
+	l.mx.Unlock()
+	return text.NewShaper(opts...)
+}
+
+// Theme font methods
+
+// SetFonts builds a shaper from the font library and switches the theme to it at the start
+// of the next frame, which it requests, so it can be called from any goroutine while the
+// window is running. Building the shaper happens on the calling goroutine. Labels resolve
+// their typeface through the library's fallback chains.
+func (t *Theme) SetFonts(lib *FontLibrary) {
+	updates := t.fontUpdateChan()
+	update := fontUpdate{lib: lib, shaper: lib.NewShaper()}
+	// Keep only the latest library if the UI has not picked up the previous one
+	select {
+	case <-updates:
+	default:
+	}
+	updates <- update
+	t.requestFrame()
+}
+
+// fontUpdateChan returns the channel of pending font updates, creating it on first use
+func (t *Theme) fontUpdateChan() chan fontUpdate {
+	t.fontsOnce.Do(func() { t.fontUpdates = make(chan fontUpdate, 1) })
+	return t.fontUpdates
+}
+
+// fontUpdate is a font library with the shaper built from it, waiting to be applied
+type fontUpdate struct {
+	lib    *FontLibrary
+	shaper *text.Shaper
+}
+
+// drainFonts swaps in a pending font library and shaper, if any
+func (t *Theme) drainFonts() {
+	select {
+	case update := <-t.fontUpdateChan():
+		t.Fonts = update.lib
+		t.Shaper = update.shaper
+	default:
+	}
+}
+
+// resolveFont expands a font's typeface through the theme's fallback chains
+func (t *Theme) resolveFont(f font.Font) font.Font {
+	if t == nil || t.Fonts == nil {
+		return f
+	}
+	return t.Fonts.Resolve(f)
+}
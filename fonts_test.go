@@ -0,0 +1,74 @@
+package fromage
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/unit"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestFontLibraryLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fonts/Go-Regular.ttf": {Data: goregular.TTF},
+		"fonts/README.txt":     {Data: []byte("not a font")},
+	}
+	lib := NewFontLibrary()
+	if err := lib.LoadFS(fsys, "fonts/*"); err != nil {
+		t.Fatalf("LoadFS failed: %v", err)
+	}
+	if n := len(lib.Faces()); n != 1 {
+		t.Errorf("Expected 1 face from the font file only, got %d", n)
+	}
+
+	if err := lib.Register("Brand", font.Bold, font.Italic, goregular.TTF); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	faces := lib.Faces()
+	if got := faces[len(faces)-1].Font; got != (font.Font{Typeface: "Brand", Weight: font.Bold, Style: font.Italic}) {
+		t.Errorf("Expected registered face to use the given metadata, got %+v", got)
+	}
+	if err := lib.LoadBytes("empty.ttf", nil); err == nil {
+		t.Error("Expected an error for an empty font")
+	}
+}
+
+func TestFontLibraryFallback(t *testing.T) {
+	lib := NewFontLibrary().
+		Fallback("Brand", "Noto Sans CJK SC", "Noto Color Emoji").
+		Fallback("", "Noto Sans Symbols")
+
+	if got := lib.Resolve(font.Font{Typeface: "Brand", Weight: font.Bold}); got.Typeface != "Brand, Noto Sans CJK SC, Noto Color Emoji" || got.Weight != font.Bold {
+		t.Errorf("Unexpected resolved font %+v", got)
+	}
+	if got := lib.Resolve(font.Font{}); got.Typeface != "Noto Sans Symbols" {
+		t.Errorf("Expected default fallback chain, got %q", got.Typeface)
+	}
+	if got := lib.Resolve(font.Font{Typeface: "Other"}); got.Typeface != "Other" {
+		t.Errorf("Expected typeface without a chain to be unchanged, got %q", got.Typeface)
+	}
+}
+
+func TestThemeSetFonts(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	lib := NewFontLibrary().AddCollection(nil).Fallback("", "Emoji")
+	woken := false
+	th.invalidate.Store(func() { woken = true })
+
+	th.SetFonts(lib)
+	if !woken {
+		t.Error("Expected SetFonts to request a frame")
+	}
+	if th.Shaper != nil || th.Fonts != nil {
+		t.Error("Expected the shaper to be swapped at the next frame, not immediately")
+	}
+	th.drainFonts()
+	if th.Shaper == nil || th.Fonts != lib {
+		t.Error("Expected the new shaper and font library after the frame started")
+	}
+	if got := th.resolveFont(font.Font{}); got.Typeface != "Emoji" {
+		t.Errorf("Expected labels to resolve through the theme's fallbacks, got %q", got.Typeface)
+	}
+}
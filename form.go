@@ -0,0 +1,277 @@
+package fromage
+
+import (
+	"context"
+	"image"
+
+	"gio.mleku.dev/io/event"
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+)
+
+// Form aggregates the validity of its fields. The submit button is disabled while a field
+// shows an error or is still being checked. Fields the user has not touched yet do not
+// show their errors, so unlike Valid they leave the button enabled: a fresh form with an
+// empty required field can still be submitted, which reveals those errors and moves the
+// focus to the first invalid field instead of calling the submit handler.
+type Form struct {
+	// Theme reference
+	theme *Theme
+	// Fields in focus order
+	fields []formEntry
+	// Submit button, disabled while a shown error remains
+	submit *ButtonLayout
+	// Handler called when a submission finds every field valid
+	onSubmit func()
+	// submitting records a submission waiting for checks to finish
+	submitting bool
+	// focus is the field to focus in the next layout
+	focus event.Tag
+}
+
+// formEntry is a field of any value type
+type formEntry interface {
+	// check validates the value if it changed
+	check()
+	// state reports whether the value is valid and whether a check is still running
+	state() (valid, pending bool)
+	// reveal shows the field's error even if the user has not edited it
+	reveal()
+	// shown reports whether the field shows an error
+	shown() bool
+	// focusTag returns the tag to focus when the field is the first invalid one
+	focusTag() event.Tag
+}
+
+// NewForm creates an empty form
+func (t *Theme) NewForm() *Form {
+	return &Form{theme: t, onSubmit: func() {}}
+}
+
+// SubmitButton makes the button submit the form and disables it while the form shows an
+// error or a check is running. This is deliberately looser than Valid: errors of fields
+// the user has not touched leave the button enabled, so clicking it reveals them and
+// focuses the first invalid field rather than leaving a fresh form's button dead.
+func (f *Form) SubmitButton(b *ButtonLayout) *Form {
+	f.submit = b.OnClick(f.Submit)
+	return f
+}
+
+// OnSubmit sets the handler called when a submission finds every field valid
+func (f *Form) OnSubmit(fn func()) *Form {
+	f.onSubmit = fn
+	return f
+}
+
+// Valid reports whether every field is valid and no check is running
+func (f *Form) Valid() bool {
+	for _, field := range f.fields {
+		field.check()
+		if valid, pending := field.state(); !valid || pending {
+			return false
+		}
+	}
+	return true
+}
+
+// Submit reveals every field's error and calls the submit handler if the form is valid,
+// once running checks finish, or else focuses the first invalid field
+func (f *Form) Submit() {
+	for _, field := range f.fields {
+		field.reveal()
+	}
+	f.submitting = true
+}
+
+// Layout validates changed fields, enables or disables the submit button and lays out
+// the form's content, which lays out the fields and the button
+func (f *Form) Layout(gtx C, content W) D {
+	blocked := false
+	for _, field := range f.fields {
+		field.check()
+		_, pending := field.state()
+		blocked = blocked || pending || field.shown()
+	}
+	if f.submit != nil {
+		f.submit.Disabled(blocked)
+	}
+	d := content(gtx)
+	f.resolve(gtx)
+	if f.focus != nil {
+		gtx.Execute(key.FocusCmd{Tag: f.focus})
+		f.focus = nil
+	}
+	return d
+}
+
+// resolve finishes a submission once no check is running
+func (f *Form) resolve(gtx C) {
+	if !f.submitting {
+		return
+	}
+	var invalid formEntry
+	for _, field := range f.fields {
+		field.check()
+		valid, pending := field.state()
+		if pending {
+			return
+		}
+		if !valid && invalid == nil {
+			invalid = field
+		}
+	}
+	f.submitting = false
+	if invalid != nil {
+		f.focus = invalid.focusTag()
+		gtx.Execute(op.InvalidateCmd{})
+		return
+	}
+	f.onSubmit()
+}
+
+// FormField shows a widget editing a value with the value's validation error under it
+type FormField[T comparable] struct {
+	// Theme reference
+	theme *Theme
+	// The value the widget edits
+	value *Value[T]
+	// The input widget
+	widget W
+	// Validators run on every change, and an optional check run on the work queue
+	validators []Validator[T]
+	async      AsyncValidator[T]
+	// Version of the value last checked, and the version it had when the field was made
+	checked uint64
+	initial uint64
+	// Current error and whether it is shown before the user edits the value
+	err      error
+	revealed bool
+	// Running asynchronous check and a counter that discards superseded results
+	task       *Task
+	generation int
+	// Tag to focus, by default the field itself
+	focus event.Tag
+}
+
+// NewFormField adds a field to the form for a widget bound to value, checked by the
+// validators in order
+func NewFormField[T comparable](f *Form, value *Value[T], widget W, validators ...Validator[T]) *FormField[T] {
+	ff := &FormField[T]{theme: f.theme, value: value, widget: widget, validators: validators}
+	ff.initial = value.Version()
+	ff.focus = ff
+	f.fields = append(f.fields, ff)
+	return ff
+}
+
+// Async sets a check run on the theme's work queue after the validators pass. The field
+// counts as invalid until it finishes.
+func (ff *FormField[T]) Async(fn AsyncValidator[T]) *FormField[T] {
+	ff.async = fn
+	ff.checked = 0
+	return ff
+}
+
+// Focus sets the tag focused when this is the first invalid field on submit, such as the
+// widget's clickable. By default the field itself takes the focus.
+func (ff *FormField[T]) Focus(tag event.Tag) *FormField[T] {
+	ff.focus = tag
+	return ff
+}
+
+// Err returns the current validation error
+func (ff *FormField[T]) Err() error {
+	ff.check()
+	return ff.err
+}
+
+// Pending reports whether the asynchronous check is running
+func (ff *FormField[T]) Pending() bool { return ff.task != nil }
+
+// check runs the validators when the value changed since the last check
+func (ff *FormField[T]) check() {
+	value, version := ff.value.load()
+	if version == ff.checked {
+		return
+	}
+	ff.checked = version
+	if ff.task != nil {
+		ff.task.Cancel()
+		ff.task = nil
+	}
+	ff.generation++
+	ff.err = nil
+	for _, validate := range ff.validators {
+		if ff.err = validate(value); ff.err != nil {
+			return
+		}
+	}
+	if ff.async == nil {
+		return
+	}
+	generation := ff.generation
+	async := ff.async
+	ff.task = SubmitFunc(ff.theme.WorkQueue(), func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, async(ctx, value)
+	}, func(_ struct{}, err error) {
+		if generation != ff.generation {
+			return
+		}
+		ff.task, ff.err = nil, err
+	})
+}
+
+func (ff *FormField[T]) state() (valid, pending bool) {
+	return ff.err == nil && ff.task == nil, ff.task != nil
+}
+
+func (ff *FormField[T]) reveal() { ff.revealed = true }
+
+func (ff *FormField[T]) shown() bool {
+	return ff.err != nil && (ff.revealed || ff.value.Version() != ff.initial)
+}
+
+func (ff *FormField[T]) focusTag() event.Tag { return ff.focus }
+
+// Layout draws the widget with the error, or a note while the check runs, under it. When
+// the field takes the focus itself, it draws a focus ring around the widget.
+func (ff *FormField[T]) Layout(gtx C) D {
+	ff.check()
+	t := ff.theme
+	return t.VFlex().
+		Rigid(ff.layoutWidget).
+		Rigid(func(gtx C) D {
+			switch {
+			case ff.shown():
+				return t.Caption(ff.err.Error()).Color(t.Colors.Error()).Layout(gtx)
+			case ff.task != nil:
+				return t.Caption("Checking…").Color(t.Colors.OnSurfaceVariant()).Layout(gtx)
+			}
+			return D{}
+		}).
+		Layout(gtx)
+}
+
+// layoutWidget draws the widget, making the field focusable when it is its own focus tag
+func (ff *FormField[T]) layoutWidget(gtx C) D {
+	if ff.focus != ff {
+		return ff.widget(gtx)
+	}
+	for {
+		if _, ok := gtx.Event(key.FocusFilter{Target: ff}); !ok {
+			break
+		}
+	}
+	d := ff.widget(gtx)
+	area := clip.Rect(image.Rectangle{Max: d.Size}).Push(gtx.Ops)
+	event.Op(gtx.Ops, ff)
+	area.Pop()
+	if gtx.Focused(ff) {
+		width := gtx.Dp(ff.theme.TextSize / 8)
+		rect := image.Rectangle{Max: d.Size}
+		ring := clip.UniformRRect(rect, gtx.Dp(ff.theme.TextSize/4)).Path(gtx.Ops)
+		paint.FillShape(gtx.Ops, ff.theme.Colors.Primary(), clip.Stroke{Path: ring, Width: float32(width)}.Op())
+	}
+	return d
+}
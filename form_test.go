@@ -0,0 +1,162 @@
+package fromage
+
+import (
+	"context"
+	"errors"
+	"image"
+	"strings"
+	"testing"
+
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+)
+
+func TestValidators(t *testing.T) {
+	if Required[string]()("") == nil || Required[string]()("x") != nil {
+		t.Error("Expected Required to reject only the zero value")
+	}
+	inRange := Range(1, 5)
+	if inRange(0) == nil || inRange(6) == nil || inRange(1) != nil || inRange(5) != nil {
+		t.Error("Expected Range to be inclusive")
+	}
+	email := Pattern(`^[^@\s]+@[^@\s]+$`, "not an email address")
+	if err := email("nobody"); err == nil || err.Error() != "not an email address" {
+		t.Errorf("Expected the pattern's message, got %v", err)
+	}
+	if email("a@b") != nil {
+		t.Error("Expected a matching string to pass")
+	}
+	even := Check(func(n int) bool { return n%2 == 0 }, "must be even")
+	if even(3) == nil || even(4) != nil {
+		t.Error("Expected Check to apply its predicate")
+	}
+}
+
+func formTestContext() C {
+	return layout.Context{Ops: new(op.Ops), Constraints: layout.Exact(image.Pt(300, 400))}
+}
+
+func TestFormSubmit(t *testing.T) {
+	th, _ := newAsyncTestTheme(t)
+	name := NewValue("")
+	age := NewValue(30)
+	submitted := 0
+	button := th.TextButton("Save")
+	form := th.NewForm().SubmitButton(button).OnSubmit(func() { submitted++ })
+	nameField := NewFormField(form, name, func(C) D { return D{} }, Required[string]())
+	ageField := NewFormField(form, age, func(C) D { return D{} }, Range(18, 130))
+	content := func(gtx C) D {
+		nameField.Layout(gtx)
+		return ageField.Layout(gtx)
+	}
+
+	// An untouched empty field does not disable the button, though the form is not
+	// Valid, but submitting reveals it
+	form.Layout(formTestContext(), content)
+	if button.disabled || form.Valid() {
+		t.Fatalf("Expected an enabled button on an invalid form, got disabled %v", button.disabled)
+	}
+	form.Submit()
+	form.resolve(formTestContext())
+	if submitted != 0 || form.focus != nameField {
+		t.Errorf("Expected the first invalid field to take the focus, got %d submissions", submitted)
+	}
+	form.Layout(formTestContext(), content)
+	if !button.disabled || !nameField.shown() {
+		t.Error("Expected the revealed error to disable the button")
+	}
+
+	// An edited invalid field shows its error
+	name.Set("Ada")
+	age.Set(12)
+	form.Layout(formTestContext(), content)
+	if !button.disabled || !ageField.shown() || nameField.Err() != nil {
+		t.Errorf("Expected only the age error, got %v and %v", nameField.Err(), ageField.Err())
+	}
+	age.Set(36)
+	form.Layout(formTestContext(), content)
+	if button.disabled {
+		t.Error("Expected a valid form to enable the button")
+	}
+	form.Submit()
+	form.Layout(formTestContext(), content)
+	if submitted != 1 {
+		t.Errorf("Expected one submission, got %d", submitted)
+	}
+}
+
+func TestFormAsyncValidation(t *testing.T) {
+	th, ready := newAsyncTestTheme(t)
+	taken := errors.New("name is taken")
+	user := NewValue("admin")
+	submitted := false
+	form := th.NewForm().OnSubmit(func() { submitted = true })
+	field := NewFormField(form, user, func(C) D { return D{} }, Required[string]()).
+		Async(func(ctx context.Context, name string) error {
+			if strings.EqualFold(name, "admin") {
+				return taken
+			}
+			return nil
+		})
+	content := field.Layout
+
+	form.Layout(formTestContext(), content)
+	if !field.Pending() || form.Valid() {
+		t.Fatal("Expected the check to be running")
+	}
+	// Submitting waits for the check
+	form.Submit()
+	form.Layout(formTestContext(), content)
+	if !form.submitting {
+		t.Fatal("Expected the submission to wait for the check")
+	}
+	awaitFrame(t, th, ready)
+	form.Layout(formTestContext(), content)
+	if !errors.Is(field.Err(), taken) || submitted || form.submitting {
+		t.Fatalf("Expected the check to fail the submission, got %v", field.Err())
+	}
+
+	// A failing validator skips the check
+	user.Set("")
+	if field.Err() == nil || field.Pending() {
+		t.Error("Expected no check for an empty name")
+	}
+	user.Set("ada")
+	form.Layout(formTestContext(), content)
+	awaitFrame(t, th, ready)
+	form.Submit()
+	form.Layout(formTestContext(), content)
+	if field.Err() != nil || !submitted {
+		t.Errorf("Expected the name to pass and submit, got %v", field.Err())
+	}
+}
+
+func TestFormSubmitClickFocusesInvalidField(t *testing.T) {
+	th := newGoldenTheme()
+	name := NewValue("")
+	submitted := false
+	button := th.TextButton("Save")
+	form := th.NewForm().SubmitButton(button).OnSubmit(func() { submitted = true })
+	field := NewFormField(form, name, func(gtx C) D { return D{Size: image.Pt(200, 40)} }, Required[string]())
+	d := th.NewDriver(image.Pt(300, 200), func(gtx C) D {
+		return form.Layout(gtx, func(gtx C) D {
+			button.Layout(gtx)
+			defer op.Offset(image.Pt(0, 100)).Push(gtx.Ops).Pop()
+			return field.Layout(gtx)
+		})
+	})
+
+	// The untouched field leaves the button enabled; clicking it reveals the error and
+	// focuses the field in the next frame
+	d.Click(image.Pt(10, 10))
+	d.Frame()
+	if submitted || !field.shown() {
+		t.Fatalf("Expected the submission to reveal the error, submitted %v", submitted)
+	}
+	if !d.Focused(field) {
+		t.Error("Expected the first invalid field to take the focus")
+	}
+	if !button.disabled {
+		t.Error("Expected the shown error to disable the button")
+	}
+}
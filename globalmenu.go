@@ -25,6 +25,7 @@ type GlobalMenu struct {
 	scrimVisible bool
 	position     image.Point
 	clickPos     image.Point // Store the right-click position
+	viewport     image.Point // Size of the area the menu opens in
 	// Animation state
 	showTime     time.Time
 	hideTime     time.Time
@@ -65,7 +66,7 @@ func (t *Theme) NewGlobalMenu() *GlobalMenu {
 		if e.Buttons == pointer.ButtonSecondary {
 			// Right-click detected
 			clickPos := image.Pt(int(e.Position.X), int(e.Position.Y))
-			gm.Show(clickPos, image.Pt(800, 600)) // Default viewport size
+			gm.Show(clickPos, gm.viewport)
 		} else if e.Buttons == pointer.ButtonPrimary && gm.scrimVisible {
 			// Left-click on scrim to close menu
 			clickPos := image.Pt(int(e.Position.X), int(e.Position.Y))
@@ -105,7 +106,6 @@ func (gm *GlobalMenu) AddItem(itemText string, action func()) *GlobalMenu {
 			}),
 		clickable:    &widget.Clickable{},
 		visible:      true,
-		showTime:     time.Now(),
 		isHiding:     false,
 		shouldRemove: false,
 	}
@@ -121,7 +121,8 @@ func (gm *GlobalMenu) Show(position image.Point, viewportSize image.Point) {
 	gm.position = gm.calculateSmartPosition(position, viewportSize)
 	gm.visible = true
 	gm.scrimVisible = true
-	gm.showTime = time.Now()
+	// The next frame sets the show and hide times
+	gm.showTime = time.Time{}
 	gm.isHiding = false
 	gm.shouldRemove = false
 	gm.animating = true
@@ -129,7 +130,7 @@ func (gm *GlobalMenu) Show(position image.Point, viewportSize image.Point) {
 
 // Hide starts the hide animation for the menu
 func (gm *GlobalMenu) Hide() {
-	gm.hideTime = time.Now()
+	gm.hideTime = time.Time{}
 	gm.isHiding = true
 	gm.scrimVisible = false
 }
@@ -144,27 +145,36 @@ func (gm *GlobalMenu) GetClickPosition() image.Point {
 	return gm.clickPos
 }
 
-// Layout renders the global menu
+// Layout renders the global menu. It must be laid out every frame, visible or not, to
+// open on right-clicks anywhere in the constraints.
 func (gm *GlobalMenu) Layout(gtx layout.Context) {
+	// Register event handler for this menu
+	gm.viewport = gtx.Constraints.Max
+	gm.eventHandler.AddToOps(gtx.Ops)
+	gm.eventHandler.ProcessEvents(gtx)
 	if !gm.visible {
 		return
 	}
 
-	now := time.Now()
+	now := gtx.Now
+	if gm.showTime.IsZero() {
+		gm.showTime = now
+	}
+	if gm.isHiding && gm.hideTime.IsZero() {
+		gm.hideTime = now
+	}
+	motion := gm.theme.motion()
 
 	// Calculate animation progress
 	var alpha float32 = 1.0
 	if !gm.isHiding {
 		// Fade in animation
-		elapsed := now.Sub(gm.showTime)
-		if elapsed < 250*time.Millisecond {
-			alpha = float32(elapsed) / float32(250*time.Millisecond)
-		}
+		alpha, _ = motion.Progress(gm.showTime, now, motion.Medium, EaseLinear)
 	} else {
 		// Fade out animation
-		elapsed := now.Sub(gm.hideTime)
-		if elapsed < 250*time.Millisecond {
-			alpha = 1.0 - (float32(elapsed) / float32(250*time.Millisecond))
+		progress, done := motion.Progress(gm.hideTime, now, motion.Medium, EaseLinear)
+		if !done {
+			alpha = 1.0 - progress
 		} else {
 			// Animation complete, mark for removal
 			gm.shouldRemove = true
@@ -181,10 +191,6 @@ func (gm *GlobalMenu) Layout(gtx layout.Context) {
 		paint.Fill(gtx.Ops, scrimColor)
 	}
 
-	// Register event handler for this menu
-	gm.eventHandler.AddToOps(gtx.Ops)
-	gm.eventHandler.ProcessEvents(gtx)
-
 	// Position the menu
 	offset := op.Offset(gm.position).Push(gtx.Ops)
 	defer offset.Pop()
@@ -293,21 +299,25 @@ func (gm *GlobalMenu) calculateSmartPosition(clickPos image.Point, viewportSize
 
 // Layout renders a menu item
 func (item *MenuItem) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
-	now := time.Now()
+	now := gtx.Now
+	if item.showTime.IsZero() {
+		item.showTime = now
+	}
+	if item.isHiding && item.hideTime.IsZero() {
+		item.hideTime = now
+	}
+	motion := th.motion()
 
 	// Calculate animation progress
 	var alpha float32 = 1.0
 	if !item.isHiding {
 		// Fade in animation
-		elapsed := now.Sub(item.showTime)
-		if elapsed < 250*time.Millisecond {
-			alpha = float32(elapsed) / float32(250*time.Millisecond)
-		}
+		alpha, _ = motion.Progress(item.showTime, now, motion.Medium, EaseLinear)
 	} else {
 		// Fade out animation
-		elapsed := now.Sub(item.hideTime)
-		if elapsed < 250*time.Millisecond {
-			alpha = 1.0 - (float32(elapsed) / float32(250*time.Millisecond))
+		progress, done := motion.Progress(item.hideTime, now, motion.Medium, EaseLinear)
+		if !done {
+			alpha = 1.0 - progress
 		} else {
 			// Animation complete, mark for removal
 			item.shouldRemove = true
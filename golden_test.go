@@ -0,0 +1,183 @@
+package fromage
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gio.mleku.dev/font/gofont"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+	"github.com/mleku/fromage/internal/offscreen"
+)
+
+// updateGolden regenerates the golden images instead of comparing against them:
+//
+//	go test -run Golden -update
+var updateGolden = flag.Bool("update", false, "regenerate golden images in testdata/golden")
+
+// goldenTolerance is the largest difference in any channel for a pixel to still match,
+// absorbing antialiasing differences between drivers
+const goldenTolerance = 8
+
+// newGoldenTheme creates a light theme with the Go fonts, so text renders the same on
+// every machine
+func newGoldenTheme() *Theme {
+	return NewThemeWithMode(context.Background(), NewColors,
+		text.NewShaper(text.NoSystemFonts(), text.WithCollection(gofont.Collection())),
+		unit.Dp(16), ThemeModeLight)
+}
+
+// TestMain selects Mesa's software rasterizer for the golden image tests, unless the
+// environment already chooses a driver; LIBGL_ALWAYS_SOFTWARE=0 renders on the GPU
+func TestMain(m *testing.M) {
+	for _, env := range offscreen.SoftwareEnv {
+		if _, ok := os.LookupEnv(env[0]); !ok {
+			os.Setenv(env[0], env[1])
+		}
+	}
+	os.Exit(m.Run())
+}
+
+// checkGolden lays out w at size with a Driver, renders it offscreen and compares it with
+// testdata/golden/<name>.png. With -update it writes the golden image instead. A missing
+// golden image fails the test, and the test is skipped where no offscreen context can be
+// created. On a mismatch the rendered image and a diff are written to the temporary
+// directory for inspection.
+func checkGolden(t *testing.T, name string, th *Theme, size image.Point, w W) {
+	t.Helper()
+	d := th.NewDriver(size, w)
+	got, err := offscreen.Render(size, d.Ops())
+	if errors.Is(err, offscreen.ErrUnavailable) {
+		t.Skipf("rendering offscreen: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("rendering offscreen: %v", err)
+	}
+	path := filepath.Join("testdata", "golden", name+".png")
+	if *updateGolden {
+		if err = writePNG(path, got); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote %s", path)
+		return
+	}
+	want, err := readPNG(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("no golden image %s; run with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	mismatched, diff := compareImages(want, got, goldenTolerance)
+	if mismatched == 0 {
+		return
+	}
+	dir := filepath.Join(os.TempDir(), "fromage-golden")
+	gotPath, diffPath := filepath.Join(dir, name+".png"), filepath.Join(dir, name+".diff.png")
+	if err = writePNG(gotPath, got); err == nil {
+		err = writePNG(diffPath, diff)
+	}
+	if err != nil {
+		t.Logf("writing mismatch images: %v", err)
+	}
+	t.Errorf("%s: %d pixels differ by more than %d; got %s, diff %s", name, mismatched, goldenTolerance, gotPath, diffPath)
+}
+
+// compareImages counts the pixels where any channel differs by more than tolerance. The
+// diff image shows matching pixels faded and mismatches in red. Images of different
+// sizes mismatch everywhere.
+func compareImages(want, got image.Image, tolerance uint8) (mismatched int, diff *image.RGBA) {
+	bounds := got.Bounds()
+	diff = image.NewRGBA(bounds)
+	if want.Bounds().Size() != bounds.Size() {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+		return bounds.Dx() * bounds.Dy(), diff
+	}
+	offset := want.Bounds().Min.Sub(bounds.Min)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			a := color.RGBAModel.Convert(want.At(x+offset.X, y+offset.Y)).(color.RGBA)
+			b := color.RGBAModel.Convert(got.At(x, y)).(color.RGBA)
+			if channelDiff(a.R, b.R) > tolerance || channelDiff(a.G, b.G) > tolerance ||
+				channelDiff(a.B, b.B) > tolerance || channelDiff(a.A, b.A) > tolerance {
+				mismatched++
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			gray := uint8((uint16(b.R) + uint16(b.G) + uint16(b.B)) / 3)
+			faded := 192 + gray/4
+			diff.Set(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+	return mismatched, diff
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return img, nil
+}
+
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func TestCompareImages(t *testing.T) {
+	want := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	got := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range want.Pix {
+		want.Pix[i], got.Pix[i] = 100, 100
+	}
+	got.Set(1, 1, color.RGBA{R: 105, G: 100, B: 96, A: 100})
+	if n, _ := compareImages(want, got, 8); n != 0 {
+		t.Errorf("Expected differences within the tolerance to match, got %d", n)
+	}
+	got.Set(2, 3, color.RGBA{R: 100, G: 120, B: 100, A: 100})
+	n, diff := compareImages(want, got, 8)
+	if n != 1 {
+		t.Errorf("Expected one mismatched pixel, got %d", n)
+	}
+	if c := diff.RGBAAt(2, 3); c.R != 255 || c.G != 0 {
+		t.Errorf("Expected the mismatch to be red in the diff, got %v", c)
+	}
+	if n, _ = compareImages(want, image.NewRGBA(image.Rect(0, 0, 4, 5)), 8); n != 20 {
+		t.Errorf("Expected a size mismatch to fail every pixel, got %d", n)
+	}
+}
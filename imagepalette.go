@@ -0,0 +1,209 @@
+package fromage
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Image palette extraction settings
+const (
+	imageSampleSize    = 128      // Images are sampled on a grid of at most this many pixels per side
+	imageClusters      = 16       // Number of k-means clusters
+	imageIterations    = 12       // Maximum k-means iterations
+	imageMinChroma     = 0.03     // Candidates duller than this are not used as seeds
+	imageMinProportion = 0.01     // Candidates covering less of the image than this are ignored
+	fallbackSeed       = 0x4285F4 // Seed used when an image has no usable color
+	imageHueSpread     = 15.0     // Minimum hue distance in degrees between returned seeds
+)
+
+// ImageSeed is a candidate seed color extracted from an image
+type ImageSeed struct {
+	// Color is the cluster's average color
+	Color color.NRGBA
+	// Proportion is the fraction of the sampled pixels in the cluster, weighted by
+	// opacity (0 to 1)
+	Proportion float64
+	// Score ranks how well the color would work as a theme seed
+	Score float64
+}
+
+// Seed returns the candidate as a 0xRRGGBB seed for NewColorsFromSeed
+func (s ImageSeed) Seed() uint32 {
+	return uint32(s.Color.R)<<16 | uint32(s.Color.G)<<8 | uint32(s.Color.B)
+}
+
+// oklab is a color in the OKLab space, used for perceptual clustering
+type oklab struct{ L, A, B float64 }
+
+// ExtractSeeds quantizes an image with k-means in OKLab and returns up to n candidate
+// seed colors, best first. Pixels count in proportion to their opacity. Near-grey and
+// tiny clusters are dropped and candidates with similar hues are merged, so the result
+// may be empty for greyscale images.
+func ExtractSeeds(img image.Image, n int) []ImageSeed {
+	pixels, weights := samplePixels(img)
+	if len(pixels) == 0 || n <= 0 {
+		return nil
+	}
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	centers, clusterWeights := kmeans(pixels, weights, imageClusters, imageIterations)
+	var candidates []ImageSeed
+	for i, c := range centers {
+		proportion := clusterWeights[i] / total
+		chroma := math.Hypot(c.A, c.B)
+		if proportion < imageMinProportion || chroma < imageMinChroma {
+			continue
+		}
+		// Favour colorful clusters, then well-represented ones, as Material's scorer does
+		score := proportion*0.7 + math.Min(chroma/0.25, 1)*0.3
+		candidates = append(candidates, ImageSeed{
+			Color:      c.nrgba(),
+			Proportion: proportion,
+			Score:      score,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	var seeds []ImageSeed
+	for _, c := range candidates {
+		_, _, hue := toOKLCH(c.Color)
+		distinct := true
+		for _, s := range seeds {
+			_, _, h := toOKLCH(s.Color)
+			if d := math.Abs(hue - h); math.Min(d, 360-d) < imageHueSpread {
+				distinct = false
+				break
+			}
+		}
+		if distinct {
+			seeds = append(seeds, c)
+		}
+		if len(seeds) == n {
+			break
+		}
+	}
+	return seeds
+}
+
+// SeedFromImage returns the best theme seed color for an image, falling back to a
+// default blue when the image has no usable color
+func SeedFromImage(img image.Image) uint32 {
+	if seeds := ExtractSeeds(img, 1); len(seeds) > 0 {
+		return seeds[0].Seed()
+	}
+	return fallbackSeed
+}
+
+// NewColorsFromImage builds a light seed color scheme from the best color in an image
+func NewColorsFromImage(img image.Image) *Colors {
+	return NewColorsFromImageWithMode(img, ThemeModeLight)
+}
+
+// NewColorsFromImageWithMode builds a seed color scheme from the best color in an image
+func NewColorsFromImageWithMode(img image.Image, mode ThemeMode) *Colors {
+	return NewColorsFromSeedWithMode(SeedFromImage(img), mode)
+}
+
+// samplePixels converts the image's visible pixels on a sampling grid to OKLab, with
+// their opacities as weights
+func samplePixels(img image.Image) (pixels []oklab, weights []float64) {
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, nil
+	}
+	step := int(math.Ceil(math.Max(float64(b.Dx()), float64(b.Dy())) / imageSampleSize))
+	if step < 1 {
+		step = 1
+	}
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				continue
+			}
+			pixels = append(pixels, toOKLab(c))
+			weights = append(weights, float64(c.A)/255)
+		}
+	}
+	return pixels, weights
+}
+
+// kmeans clusters the weighted pixels into at most k clusters, seeding the centers
+// deterministically with farthest-point initialisation, and returns the centers with the
+// total weight of their pixels
+func kmeans(pixels []oklab, weights []float64, k, iterations int) ([]oklab, []float64) {
+	centers := []oklab{pixels[0]}
+	nearest := make([]float64, len(pixels))
+	for i := range nearest {
+		nearest[i] = math.Inf(1)
+	}
+	for len(centers) < k {
+		last := centers[len(centers)-1]
+		far, farDist := -1, 0.0
+		for i, p := range pixels {
+			nearest[i] = math.Min(nearest[i], p.dist(last))
+			if nearest[i] > farDist {
+				far, farDist = i, nearest[i]
+			}
+		}
+		if far < 0 {
+			break // Fewer distinct colors than clusters
+		}
+		centers = append(centers, pixels[far])
+	}
+	assign := make([]int, len(pixels))
+	totals := make([]float64, len(centers))
+	for it := 0; it < iterations; it++ {
+		changed := false
+		clear(totals)
+		sums := make([]oklab, len(centers))
+		for i, p := range pixels {
+			best, bestDist := 0, math.Inf(1)
+			for j, c := range centers {
+				if d := p.dist(c); d < bestDist {
+					best, bestDist = j, d
+				}
+			}
+			if it == 0 || assign[i] != best {
+				changed = true
+			}
+			assign[i] = best
+			w := weights[i]
+			totals[best] += w
+			sums[best].L += p.L * w
+			sums[best].A += p.A * w
+			sums[best].B += p.B * w
+		}
+		for j := range centers {
+			if w := totals[j]; w > 0 {
+				centers[j] = oklab{sums[j].L / w, sums[j].A / w, sums[j].B / w}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return centers, totals
+}
+
+// dist returns the squared distance between two OKLab colors
+func (c oklab) dist(o oklab) float64 {
+	dl, da, db := c.L-o.L, c.A-o.A, c.B-o.B
+	return dl*dl + da*da + db*db
+}
+
+// nrgba converts an OKLab color back to 8-bit sRGB
+func (c oklab) nrgba() color.NRGBA {
+	r, g, b := clipOKLCH(c.L, math.Hypot(c.A, c.B), math.Atan2(c.B, c.A)*180/math.Pi)
+	return color.NRGBA{R: encodeSRGB(r), G: encodeSRGB(g), B: encodeSRGB(b), A: 255}
+}
+
+// toOKLab converts a color to OKLab
+func toOKLab(c color.NRGBA) oklab {
+	l, chroma, hue := toOKLCH(c)
+	h := hue * math.Pi / 180
+	return oklab{l, chroma * math.Cos(h), chroma * math.Sin(h)}
+}
@@ -0,0 +1,89 @@
+package fromage
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// stripes returns an image filled with horizontal bands of the given colors and heights
+func stripes(width int, bands []color.NRGBA, heights []int) *image.NRGBA {
+	total := 0
+	for _, h := range heights {
+		total += h
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, total))
+	y := 0
+	for i, c := range bands {
+		for end := y + heights[i]; y < end; y++ {
+			for x := 0; x < width; x++ {
+				img.SetNRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+func TestExtractSeedsPrefersDominantColor(t *testing.T) {
+	green := rgb(0x2E7D32)
+	orange := rgb(0xFF9800)
+	img := stripes(64, []color.NRGBA{green, orange, rgb(0xFFFFFF)}, []int{60, 20, 20})
+
+	seeds := ExtractSeeds(img, 3)
+	if len(seeds) != 2 {
+		t.Fatalf("Expected 2 colorful seeds (white ignored), got %d", len(seeds))
+	}
+	if seeds[0].Color != green {
+		t.Errorf("Expected dominant green first, got %v", seeds[0].Color)
+	}
+	if seeds[1].Color != orange {
+		t.Errorf("Expected orange second, got %v", seeds[1].Color)
+	}
+	if math.Abs(seeds[0].Proportion-0.6) > 0.02 {
+		t.Errorf("Expected green proportion near 0.6, got %.2f", seeds[0].Proportion)
+	}
+	if seeds[0].Seed() != 0x2E7D32 {
+		t.Errorf("Expected seed 0x2E7D32, got %06X", seeds[0].Seed())
+	}
+}
+
+func TestExtractSeedsWeightsByAlpha(t *testing.T) {
+	green, orange := rgb(0x2E7D32), rgb(0xFF9800)
+	faint := green
+	faint.A = 64
+	img := stripes(64, []color.NRGBA{faint, orange}, []int{60, 40})
+	seeds := ExtractSeeds(img, 2)
+	if len(seeds) != 2 || seeds[0].Color != orange || seeds[1].Color != green {
+		t.Fatalf("Expected opaque orange before faint green, got %v", seeds)
+	}
+	// 40 opaque rows against 60 rows at a quarter opacity
+	if want := 40 / (40 + 60*64/255.0); math.Abs(seeds[0].Proportion-want) > 0.02 {
+		t.Errorf("Expected orange proportion near %.2f, got %.2f", want, seeds[0].Proportion)
+	}
+	// A translucent image still has a seed
+	translucent := orange
+	translucent.A = 200
+	if seed := SeedFromImage(stripes(16, []color.NRGBA{translucent}, []int{16})); seed != 0xFF9800 {
+		t.Errorf("Expected the translucent image's color as seed, got %06X", seed)
+	}
+}
+
+func TestSeedFromGreyscaleImageFallsBack(t *testing.T) {
+	img := stripes(16, []color.NRGBA{rgb(0x000000), rgb(0x808080)}, []int{8, 8})
+	if seed := SeedFromImage(img); seed != fallbackSeed {
+		t.Errorf("Expected fallback seed %06X, got %06X", fallbackSeed, seed)
+	}
+	if seeds := ExtractSeeds(image.NewNRGBA(image.Rectangle{}), 1); seeds != nil {
+		t.Errorf("Expected no seeds from an empty image, got %v", seeds)
+	}
+}
+
+func TestNewColorsFromImage(t *testing.T) {
+	img := stripes(32, []color.NRGBA{rgb(0x1565C0)}, []int{32})
+	colors := NewColorsFromImageWithMode(img, ThemeModeDark)
+	want := NewColorsFromSeedWithMode(0x1565C0, ThemeModeDark)
+	if colors.Primary() != want.Primary() {
+		t.Errorf("Expected primary %v from image seed, got %v", want.Primary(), colors.Primary())
+	}
+}
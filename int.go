@@ -14,6 +14,7 @@ import (
 
 // Int is a slider widget for selecting integer values
 type Int struct {
+	theme      *Theme
 	value      int
 	clickable  *widget.Clickable
 	pos        float32 // position normalized to [0, 1]
@@ -23,11 +24,13 @@ type Int struct {
 	min, max   int
 	dragging   bool
 	drag       gesture.Drag
+	bound      binding[int] // observable value the slider shows and edits
 }
 
 // NewInt creates a new integer slider
 func (t *Theme) NewInt() *Int {
 	return &Int{
+		theme:      t,
 		clickable:  t.Pool.GetClickable(),
 		changeHook: func(int) {},
 		min:        0,
@@ -46,6 +49,13 @@ func (i *Int) Value() int {
 	return i.value
 }
 
+// Bind makes the slider show and edit an observable value
+func (i *Int) Bind(v *Value[int]) *Int {
+	i.bound.bind(v)
+	v.Invalidates(i.theme)
+	return i
+}
+
 // SetRange sets the min and max values
 func (i *Int) SetRange(min, max int) *Int {
 	i.min = min
@@ -73,6 +83,11 @@ func (i *Int) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 	size := gtx.Constraints.Min
 	i.length = float32(size.X)
 
+	// Follow changes to the bound value
+	if value, ok := i.bound.pull(); ok {
+		i.value = value
+	}
+
 	// Update position based on current value
 	if i.min != i.max {
 		i.pos = float32(i.value-i.min) / float32(i.max-i.min)
@@ -105,6 +120,7 @@ func (i *Int) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 			// Convert to integer value
 			i.value = i.min + int(i.pos*float32(i.max-i.min)+0.5)
 			i.changed = true
+			i.bound.push(i.value)
 			i.changeHook(i.value)
 		case pointer.Drag:
 			if i.dragging {
@@ -119,6 +135,7 @@ func (i *Int) Layout(gtx layout.Context, th *Theme) layout.Dimensions {
 				// Convert to integer value
 				i.value = i.min + int(i.pos*float32(i.max-i.min)+0.5)
 				i.changed = true
+				i.bound.push(i.value)
 				i.changeHook(i.value)
 			}
 		case pointer.Release:
@@ -0,0 +1,43 @@
+// Package offscreen renders frames without a window, for the golden image tests and the
+// screenshots tool. It does not configure the graphics driver; callers that want the same
+// pixels with or without a GPU select Mesa's software rasterizer with SoftwareEnv before
+// the first render.
+package offscreen
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"gio.mleku.dev/gpu/headless"
+	"gio.mleku.dev/op"
+)
+
+// ErrUnavailable reports that no offscreen graphics context could be created, such as on
+// a machine without EGL
+var ErrUnavailable = errors.New("offscreen rendering unavailable")
+
+// SoftwareEnv is the environment selecting Mesa's llvmpipe rasterizer on a display-less
+// EGL platform, so renders are the same on every machine
+var SoftwareEnv = [][2]string{
+	{"LIBGL_ALWAYS_SOFTWARE", "1"},
+	{"GALLIUM_DRIVER", "llvmpipe"},
+	{"EGL_PLATFORM", "surfaceless"},
+}
+
+// Render renders a frame's operations at size pixels and reads back the image
+func Render(size image.Point, ops *op.Ops) (*image.RGBA, error) {
+	win, err := headless.NewWindow(size.X, size.Y)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer win.Release()
+	if err = win.Frame(ops); err != nil {
+		return nil, fmt.Errorf("rendering frame: %w", err)
+	}
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	if err = win.Screenshot(img); err != nil {
+		return nil, fmt.Errorf("reading frame: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,86 @@
+// Package screenshot is the environment contract between the screenshots tool and the
+// windows that allow screenshots: the tool sets the FROMAGE_SCREENSHOT variables when it
+// runs a demo, and the window's RunFrames reads them and renders to a PNG file instead of
+// opening the window.
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables of screenshot mode. PathEnv names the PNG file to write; the
+// others optionally set the size in dp such as "1200x800", the pixels per dp, and the
+// theme mode, "light" or "dark".
+const (
+	PathEnv  = "FROMAGE_SCREENSHOT"
+	SizeEnv  = "FROMAGE_SCREENSHOT_SIZE"
+	ScaleEnv = "FROMAGE_SCREENSHOT_SCALE"
+	ModeEnv  = "FROMAGE_SCREENSHOT_MODE"
+)
+
+// Spec describes an offscreen render of a window's content
+type Spec struct {
+	// Path of the PNG file
+	Path string
+	// Size in dp and pixels per dp
+	Size  image.Point
+	Scale float32
+	// Theme mode, or the theme's own mode if empty
+	Mode string
+}
+
+// defaultSize is the size in dp of screenshots that do not set one
+var defaultSize = image.Pt(1200, 800)
+
+// Env returns the environment variables that request the screenshot from a demo
+func Env(spec Spec) []string {
+	env := []string{
+		PathEnv + "=" + spec.Path,
+		fmt.Sprintf("%s=%dx%d", SizeEnv, spec.Size.X, spec.Size.Y),
+		ScaleEnv + "=" + strconv.FormatFloat(float64(spec.Scale), 'g', -1, 32),
+	}
+	if spec.Mode != "" {
+		env = append(env, ModeEnv+"="+spec.Mode)
+	}
+	return env
+}
+
+// ParseSize parses a size in dp such as "1200x800"
+func ParseSize(s string) (image.Point, error) {
+	w, h, ok := strings.Cut(s, "x")
+	x, errX := strconv.Atoi(w)
+	y, errY := strconv.Atoi(h)
+	if !ok || errX != nil || errY != nil || x <= 0 || y <= 0 {
+		return image.Point{}, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT", s)
+	}
+	return image.Pt(x, y), nil
+}
+
+// FromEnv returns the screenshot the environment requests, if any
+func FromEnv() (spec Spec, ok bool, err error) {
+	spec = Spec{Path: os.Getenv(PathEnv), Size: defaultSize, Scale: 1}
+	if spec.Path == "" {
+		return spec, false, nil
+	}
+	if s := os.Getenv(SizeEnv); s != "" {
+		if spec.Size, err = ParseSize(s); err != nil {
+			return spec, true, err
+		}
+	}
+	if s := os.Getenv(ScaleEnv); s != "" {
+		scale, err := strconv.ParseFloat(s, 32)
+		if err != nil || scale <= 0 {
+			return spec, true, fmt.Errorf("invalid scale %q", s)
+		}
+		spec.Scale = float32(scale)
+	}
+	spec.Mode = os.Getenv(ModeEnv)
+	if spec.Mode != "" && spec.Mode != "light" && spec.Mode != "dark" {
+		return spec, true, fmt.Errorf("invalid theme mode %q, expected light or dark", spec.Mode)
+	}
+	return spec, true, nil
+}
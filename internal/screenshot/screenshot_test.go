@@ -0,0 +1,38 @@
+package screenshot
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	if size, err := ParseSize("1200x800"); err != nil || size != image.Pt(1200, 800) {
+		t.Errorf("Expected 1200x800, got %v, %v", size, err)
+	}
+	for _, s := range []string{"", "1200", "x800", "0x800", "12ox800", "-1x5"} {
+		if _, err := ParseSize(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(PathEnv, "")
+	if _, ok, err := FromEnv(); ok || err != nil {
+		t.Fatalf("Expected no screenshot without %s, got %v, %v", PathEnv, ok, err)
+	}
+	want := Spec{Path: "out.png", Size: image.Pt(640, 480), Scale: 1.5, Mode: "dark"}
+	for _, kv := range Env(want) {
+		k, v, _ := strings.Cut(kv, "=")
+		t.Setenv(k, v)
+	}
+	spec, ok, err := FromEnv()
+	if !ok || err != nil || spec != want {
+		t.Fatalf("Expected %+v, got %+v, %v, %v", want, spec, ok, err)
+	}
+	t.Setenv(ModeEnv, "sepia")
+	if _, _, err = FromEnv(); err == nil {
+		t.Error("Expected an unknown theme mode to be rejected")
+	}
+}
@@ -27,21 +27,24 @@ type Label struct {
 	maxLines int
 	// Text size
 	textSize unit.Sp
-	// Text shaper
-	shaper *text.Shaper
+	// Line height as a multiple of the text size (0 = shaper default)
+	lineHeight float32
+	// Selection state, set when the text is selectable
+	selectable *widget.Selectable
 }
 
-// NewLabel creates a new label with default settings
+// NewLabel creates a new label with default settings, in the body large font and size
+// with the shaper's default line height
 func (t *Theme) NewLabel() *Label {
+	body := t.typography().BodyLarge
 	return &Label{
 		theme:     t,
 		text:      "",
-		font:      font.Font{},
+		font:      body.Font,
 		color:     t.Colors.OnBackground(),
 		alignment: text.Start,
 		maxLines:  0,
-		textSize:  unit.Sp(t.TextSize),
-		shaper:    t.Shaper,
+		textSize:  body.Size(t.TextSize),
 	}
 }
 
@@ -87,12 +90,54 @@ func (l *Label) TextScale(scale float32) *Label {
 	return l
 }
 
+// Style sets the font, size and line height from a type style
+func (l *Label) Style(style TypeStyle) *Label {
+	l.font = style.Font
+	l.textSize = style.Size(l.theme.TextSize)
+	l.lineHeight = style.LineHeight
+	return l
+}
+
+// LineHeight sets the line height as a multiple of the text size
+func (l *Label) LineHeight(scale float32) *Label {
+	l.lineHeight = scale
+	return l
+}
+
+// Selectable makes the text selectable with the mouse and keyboard: drag to select,
+// double-click for a word, triple-click for a line, and Ctrl+C to copy. The selection
+// lives in the label, so a selectable label must be kept across frames.
+func (l *Label) Selectable(selectable bool) *Label {
+	switch {
+	case selectable && l.selectable == nil:
+		l.selectable = &widget.Selectable{}
+	case !selectable:
+		l.selectable = nil
+	}
+	return l
+}
+
+// IsSelectable reports whether the text is selectable
+func (l *Label) IsSelectable() bool { return l.selectable != nil }
+
+// SelectedText returns the selected text of a selectable label
+func (l *Label) SelectedText() string {
+	if l.selectable == nil {
+		return ""
+	}
+	return l.selectable.SelectedText()
+}
+
 // Layout renders the label
 func (l *Label) Layout(g C) D {
+	if l.selectable != nil {
+		return l.layoutSelectable(g)
+	}
 	// Create the underlying Gio label
 	label := widget.Label{
-		Alignment: l.alignment,
-		MaxLines:  l.maxLines,
+		Alignment:       l.alignment,
+		MaxLines:        l.maxLines,
+		LineHeightScale: l.lineHeight,
 	}
 
 	// Record color operation
@@ -101,52 +146,114 @@ func (l *Label) Layout(g C) D {
 	textColor := textColorMacro.Stop()
 
 	// Layout the label
-	return label.Layout(g, l.shaper, l.font, l.textSize, l.text, textColor)
+	return label.Layout(g, l.theme.Shaper, l.theme.resolveFont(l.font), l.textSize, l.text, textColor)
 }
 
-// Convenience methods for common text styles
+// layoutSelectable renders the label with selection highlighted in the primary container color
+func (l *Label) layoutSelectable(g C) D {
+	l.selectable.Alignment = l.alignment
+	l.selectable.MaxLines = l.maxLines
+	l.selectable.LineHeightScale = l.lineHeight
+	if l.selectable.Text() != l.text {
+		l.selectable.SetText(l.text)
+	}
+
+	textColorMacro := op.Record(g.Ops)
+	paint.ColorOp{Color: l.color}.Add(g.Ops)
+	textColor := textColorMacro.Stop()
+
+	selectionColorMacro := op.Record(g.Ops)
+	paint.ColorOp{Color: l.theme.Colors.PrimaryContainer()}.Add(g.Ops)
+	selectionColor := selectionColorMacro.Stop()
+
+	return l.selectable.Layout(g, l.theme.Shaper, l.theme.resolveFont(l.font), l.textSize, textColor, selectionColor)
+}
+
+// Convenience methods for common text styles. They take their fonts and sizes from the
+// theme's type scale with the shaper's default line height. With the default scale they
+// keep the factors of TextSize they have always had, so existing layouts do not change
+// size; a theme that scales or replaces a style scales its labels with it. Styled with a
+// style of the theme's Typography gives the Material 3 sizes and line heights.
 
 // H1 creates a large heading
 func (t *Theme) H1(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(2) // 96/16
+	return t.heading(1, 2, text) // 96/16
 }
 
 // H2 creates a medium heading
 func (t *Theme) H2(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(1.75) // 60/16
+	return t.heading(2, 1.75, text) // 60/16
 }
 
 // H3 creates a small heading
 func (t *Theme) H3(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(1.5) // 48/16
+	return t.heading(3, 1.5, text) // 48/16
 }
 
 // H4 creates a smaller heading
 func (t *Theme) H4(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(1.25) // 34/16
+	return t.heading(4, 1.25, text) // 34/16
 }
 
 // H5 creates a small heading
 func (t *Theme) H5(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(1) // 24/16
+	return t.heading(5, 1, text) // 24/16
 }
 
 // H6 creates the smallest heading
 func (t *Theme) H6(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(0.75) // 20/16
+	return t.heading(6, 0.75, text) // 20/16
+}
+
+// heading creates a heading label at a level from 1 to 6
+func (t *Theme) heading(level int, factor float32, text string) *Label {
+	return t.legacyStyled(func(ty *Typography) TypeStyle { return headingStyle(ty, level) }, factor, text)
+}
+
+// headingStyle returns the type scale style of the H1 to H6 headings
+func headingStyle(ty *Typography, level int) TypeStyle {
+	switch level {
+	case 1:
+		return ty.DisplayLarge
+	case 2:
+		return ty.DisplayMedium
+	case 3:
+		return ty.DisplaySmall
+	case 4:
+		return ty.HeadlineMedium
+	case 5:
+		return ty.HeadlineSmall
+	default:
+		return ty.TitleLarge
+	}
 }
 
 // Body1 creates normal body text
 func (t *Theme) Body1(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(1.0)
+	return t.legacyStyled(func(ty *Typography) TypeStyle { return ty.BodyLarge }, 1, text)
 }
 
 // Body2 creates smaller body text
 func (t *Theme) Body2(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(0.75) // 14/16
+	return t.legacyStyled(func(ty *Typography) TypeStyle { return ty.BodyMedium }, 0.75, text) // 14/16
 }
 
 // Caption creates caption text
 func (t *Theme) Caption(text string) *Label {
-	return t.NewLabel().Text(text).TextScale(0.5) // 12/16
+	return t.legacyStyled(func(ty *Typography) TypeStyle { return ty.BodySmall }, 0.5, text) // 12/16
+}
+
+// referenceTypography is the default type scale the original factors are relative to
+var referenceTypography = NewTypography()
+
+// legacyStyled creates a label in the style style picks from the theme's type scale, with
+// the shaper's default line height. Its size is factor times TextSize for the default
+// scale, and follows the theme's style in proportion.
+func (t *Theme) legacyStyled(style func(*Typography) TypeStyle, factor float32, text string) *Label {
+	s := style(t.typography())
+	if ref := style(referenceTypography); ref.Scale > 0 {
+		s.Scale *= factor / ref.Scale
+	}
+	s.LineHeight = 0
+	return t.Styled(s, text)
 }
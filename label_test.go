@@ -2,8 +2,12 @@ package fromage
 
 import (
 	"context"
+	"image"
+	"strings"
 	"testing"
+	"time"
 
+	"gio.mleku.dev/io/key"
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
 )
@@ -70,3 +74,64 @@ func TestLabelConvenienceMethods(t *testing.T) {
 		t.Errorf("Caption text should be 'Caption text', got '%s'", caption.text)
 	}
 }
+
+func TestLabelSelectable(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+
+	label := th.H3("error 0x2A").Selectable(true)
+	if !label.IsSelectable() || label.selectable == nil {
+		t.Fatal("Expected the heading to become selectable")
+	}
+	state := label.selectable
+	if label.Selectable(true).selectable != state {
+		t.Error("Expected enabling twice to keep the selection state")
+	}
+	if label.SelectedText() != "" {
+		t.Error("Expected no selection initially")
+	}
+	if label.Selectable(false).IsSelectable() || label.SelectedText() != "" {
+		t.Error("Expected the label to stop being selectable")
+	}
+	if th.Body1("plain").IsSelectable() {
+		t.Error("Expected labels not to be selectable by default")
+	}
+}
+
+func TestLabelSelectableDrag(t *testing.T) {
+	th := newGoldenTheme()
+	label := th.Body1("error 0x2A").Selectable(true)
+	d := th.NewDriver(image.Pt(300, 30), label.Layout)
+
+	d.Drag(image.Pt(0, 10), image.Pt(299, 10))
+	if got := label.SelectedText(); got != "error 0x2A" {
+		t.Errorf("Expected dragging across the label to select it all, got %q", got)
+	}
+
+	// The click focused the label, so the shortcut copies the selection
+	d.Press("C", key.ModShortcut)
+	if got, ok := d.Clipboard(); !ok || got != "error 0x2A" {
+		t.Errorf("Expected Ctrl+C to copy the selection, got %q, %v", got, ok)
+	}
+}
+
+func TestLabelSelectableMultiClick(t *testing.T) {
+	th := newGoldenTheme()
+	label := th.Body1("error 0x2A\nsecond line").Selectable(true)
+	d := th.NewDriver(image.Pt(300, 60), label.Layout)
+
+	// A double click selects the word under the pointer
+	d.Click(image.Pt(10, 10))
+	d.Click(image.Pt(10, 10))
+	if got := strings.TrimSpace(label.SelectedText()); got != "error" {
+		t.Errorf("Expected a double click to select the word, got %q", got)
+	}
+
+	// A triple click selects the line
+	d.Advance(time.Second)
+	for range 3 {
+		d.Click(image.Pt(10, 10))
+	}
+	if got := strings.TrimSpace(label.SelectedText()); got != "error 0x2A" {
+		t.Errorf("Expected a triple click to select the line, got %q", got)
+	}
+}
@@ -0,0 +1,374 @@
+package fromage
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strconv"
+
+	"gio.mleku.dev/f32"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+)
+
+// Markdown renders a CommonMark document in a scrollable, themed view. Paragraphs and
+// headings are RichText, so links are clickable and text can be selected and copied.
+type Markdown struct {
+	// Theme reference
+	theme *Theme
+	// Markdown source
+	source string
+	// Widgets of the top-level blocks
+	widgets []W
+	// Scrolling list of the blocks
+	list layout.List
+	// Scrollbar beside the list
+	scrollbar *Scrollbar
+	// Visible fraction of the content, from the last frame
+	viewport float32
+	// Called with the destination when a link is clicked
+	onLink func(link string)
+	// Loads the image for an image block's source
+	loadImage func(src string) (image.Image, error)
+	// Image loads by source, run on the theme's work queue
+	images map[string]*Async[paint.ImageOp]
+}
+
+// NewMarkdown creates a markdown view of the given source
+func (t *Theme) NewMarkdown(source string) *Markdown {
+	m := &Markdown{
+		theme:     t,
+		list:      layout.List{Axis: layout.Vertical},
+		scrollbar: t.NewScrollbar(Vertical).SetWidth(t.TextSize / 2),
+		images:    make(map[string]*Async[paint.ImageOp]),
+	}
+	m.scrollbar.SetHook(m.scrollTo)
+	return m.Source(source)
+}
+
+// Source replaces the markdown source and scrolls back to the top
+func (m *Markdown) Source(source string) *Markdown {
+	m.source = source
+	m.widgets = m.build(parseMarkdown(source), 0)
+	m.list.Position = layout.Position{}
+	return m
+}
+
+// Text returns the markdown source
+func (m *Markdown) Text() string { return m.source }
+
+// OnLink sets the callback for clicked links
+func (m *Markdown) OnLink(fn func(link string)) *Markdown {
+	m.onLink = fn
+	return m
+}
+
+// Images sets the loader for image blocks. It is called once per source on the theme's
+// work queue; the alternative text is shown while it runs, without a loader, or when it
+// fails.
+func (m *Markdown) Images(load func(src string) (image.Image, error)) *Markdown {
+	m.loadImage = load
+	for _, a := range m.images {
+		a.Cancel()
+	}
+	m.images = make(map[string]*Async[paint.ImageOp])
+	return m
+}
+
+// Layout renders the document, scrolling vertically
+func (m *Markdown) Layout(gtx C) D {
+	spacing := m.theme.TextSize / 2
+	return m.theme.HFlex().
+		Flexed(1, func(gtx C) D {
+			return m.list.Layout(gtx, len(m.widgets), func(gtx C, i int) D {
+				return layout.Inset{Bottom: spacing, Right: spacing}.Layout(gtx, m.widgets[i])
+			})
+		}).
+		Rigid(func(gtx C) D {
+			if !m.syncScrollbar() {
+				return D{}
+			}
+			gtx.Constraints.Min.Y = gtx.Constraints.Max.Y
+			d := m.scrollbar.Layout(gtx, m.theme)
+			if m.scrollbar.Changed() {
+				gtx.Execute(op.InvalidateCmd{})
+			}
+			return d
+		}).
+		Layout(gtx)
+}
+
+// syncScrollbar updates the scrollbar from the list position and reports whether the
+// content overflows
+func (m *Markdown) syncScrollbar() bool {
+	pos := m.list.Position
+	if pos.Length <= 0 || len(m.widgets) == 0 {
+		return false
+	}
+	length := float32(pos.Length)
+	avg := length / float32(len(m.widgets))
+	start := clamp01((float32(pos.First)*avg + float32(pos.Offset)) / length)
+	end := clamp01((float32(pos.First+pos.Count)*avg + float32(pos.OffsetLast)) / length)
+	m.viewport = end - start
+	if m.viewport >= 1 {
+		return false
+	}
+	m.scrollbar.SetViewport(m.viewport)
+	if !m.scrollbar.dragging {
+		m.scrollbar.SetPosition(start / (1 - m.viewport))
+	}
+	return true
+}
+
+// scrollTo moves the list to a scrollbar position
+func (m *Markdown) scrollTo(position float32) {
+	pos := m.list.Position
+	if pos.Length <= 0 || len(m.widgets) == 0 {
+		return
+	}
+	avg := float32(pos.Length) / float32(len(m.widgets))
+	target := position * (1 - m.viewport) * float32(pos.Length)
+	m.list.Position.First = int(target / avg)
+	m.list.Position.Offset = int(target - float32(m.list.Position.First)*avg)
+	m.list.Position.BeforeEnd = true
+}
+
+// build creates the widgets of a sequence of blocks at a list nesting depth
+func (m *Markdown) build(blocks []*mdBlock, depth int) []W {
+	widgets := make([]W, 0, len(blocks))
+	for _, b := range blocks {
+		widgets = append(widgets, m.block(b, depth))
+	}
+	return widgets
+}
+
+// block creates the widget of a block
+func (m *Markdown) block(b *mdBlock, depth int) W {
+	t := m.theme
+	switch b.kind {
+	case mdHeading:
+		return m.inline(m.richText(b.text, headingStyle(t.typography(), b.level), Span{}))
+	case mdCode:
+		return m.codeBlock(b)
+	case mdQuote:
+		return m.quote(m.build(b.children, depth))
+	case mdList:
+		return m.listBlock(b, depth)
+	case mdTable:
+		return m.tableBlock(b)
+	case mdRule:
+		return func(gtx C) D {
+			size := image.Pt(gtx.Constraints.Max.X, gtx.Dp(1))
+			paint.FillShape(gtx.Ops, t.Colors.OutlineVariant(), clip.Rect{Max: size}.Op())
+			return D{Size: size}
+		}
+	case mdImage:
+		return m.imageBlock(b)
+	default:
+		return m.inline(m.richText(b.text, t.typography().BodyLarge, Span{}))
+	}
+}
+
+// richText creates a rich text from inline markdown
+func (m *Markdown) richText(src string, style TypeStyle, base Span) *RichText {
+	rt := m.theme.NewRichText().Style(style).OnLink(func(link string) {
+		if m.onLink != nil {
+			m.onLink(link)
+		}
+	})
+	for _, span := range parseInline(src, base) {
+		rt.Span(span)
+	}
+	return rt
+}
+
+// inline lays out a rich text, highlighting code spans in the current theme colors
+func (m *Markdown) inline(rt *RichText) W {
+	return func(gtx C) D {
+		for i := range rt.spans {
+			if rt.spans[i].Monospace {
+				rt.spans[i].Background = m.theme.Colors.SurfaceVariant()
+			}
+		}
+		return rt.Layout(gtx)
+	}
+}
+
+// column stacks widgets vertically with block spacing
+func (m *Markdown) column(gtx C, widgets []W) D {
+	flex := m.theme.VFlex()
+	for i, w := range widgets {
+		if i > 0 {
+			flex.Rigid(layout.Spacer{Height: m.theme.TextSize / 2}.Layout)
+		}
+		flex.Rigid(w)
+	}
+	return flex.Layout(gtx)
+}
+
+// codeBlock lays out a code block as monospace text on a card
+func (m *Markdown) codeBlock(b *mdBlock) W {
+	t := m.theme
+	rt := t.NewRichText().Style(t.typography().BodyMedium).Span(Span{Text: b.text, Monospace: true})
+	return func(gtx C) D {
+		rt.spans[0].Color = t.Colors.OnSurfaceVariant()
+		gtx.Constraints.Min.X = gtx.Constraints.Max.X
+		return t.NewCardWithColor(t.Colors.SurfaceVariant(), rt.Layout).
+			Padding(t.TextSize * 3 / 4).
+			Layout(gtx)
+	}
+}
+
+// quote lays out block quote content indented beside a vertical bar
+func (m *Markdown) quote(children []W) W {
+	return func(gtx C) D {
+		bar := gtx.Dp(m.theme.TextSize / 4)
+		indent := bar + gtx.Dp(m.theme.TextSize*3/4)
+		macro := op.Record(gtx.Ops)
+		cgtx := gtx
+		cgtx.Constraints.Min.X = 0
+		cgtx.Constraints.Max.X = max(gtx.Constraints.Max.X-indent, 0)
+		off := op.Offset(image.Pt(indent, 0)).Push(gtx.Ops)
+		d := m.column(cgtx, children)
+		off.Pop()
+		content := macro.Stop()
+		paint.FillShape(gtx.Ops, m.theme.Colors.OutlineVariant(), clip.Rect{Max: image.Pt(bar, d.Size.Y)}.Op())
+		content.Add(gtx.Ops)
+		return D{Size: image.Pt(d.Size.X+indent, d.Size.Y)}
+	}
+}
+
+// bullets are the list markers of successive nesting depths
+var bullets = []string{"•", "◦", "▪"}
+
+// listBlock lays out list items beside their bullets or numbers
+func (m *Markdown) listBlock(b *mdBlock, depth int) W {
+	t := m.theme
+	items := make([][]W, len(b.items))
+	for i, item := range b.items {
+		items[i] = m.build(item, depth+1)
+	}
+	return func(gtx C) D {
+		markerWidth := gtx.Dp(t.TextSize * 2)
+		flex := t.VFlex()
+		for i, item := range items {
+			marker := bullets[depth%len(bullets)]
+			if b.ordered {
+				marker = strconv.Itoa(b.start+i) + "."
+			}
+			flex.Rigid(func(gtx C) D {
+				return t.HFlex().
+					Rigid(func(gtx C) D {
+						d := t.Body1(marker).Color(t.Colors.OnBackground()).Layout(gtx)
+						d.Size.X = markerWidth
+						return d
+					}).
+					Flexed(1, func(gtx C) D { return m.column(gtx, item) }).
+					Layout(gtx)
+			})
+		}
+		return flex.Layout(gtx)
+	}
+}
+
+// tableBlock lays out a table in equal-width columns with a highlighted header row
+func (m *Markdown) tableBlock(b *mdBlock) W {
+	t := m.theme
+	cells := make([][]*RichText, len(b.rows))
+	for r, row := range b.rows {
+		for c, cell := range row {
+			rt := m.richText(cell, t.typography().BodyMedium, Span{Bold: r == 0}).Alignment(b.align[c])
+			cells[r] = append(cells[r], rt)
+		}
+	}
+	return func(gtx C) D {
+		width := gtx.Constraints.Max.X
+		colWidth := width / max(len(b.align), 1)
+		pad := gtx.Dp(t.TextSize / 2)
+		line := gtx.Dp(1)
+		y := 0
+		for r, row := range cells {
+			// Record the row's cells to find its height
+			macro := op.Record(gtx.Ops)
+			height := 0
+			for c, rt := range row {
+				cgtx := gtx
+				cgtx.Constraints = layout.Constraints{Max: image.Pt(max(colWidth-2*pad, 0), gtx.Constraints.Max.Y)}
+				cgtx.Constraints.Min.X = cgtx.Constraints.Max.X
+				off := op.Offset(image.Pt(c*colWidth+pad, pad)).Push(gtx.Ops)
+				d := m.inline(rt)(cgtx)
+				off.Pop()
+				height = max(height, d.Size.Y+2*pad)
+			}
+			call := macro.Stop()
+			if r == 0 {
+				paint.FillShape(gtx.Ops, t.Colors.SurfaceVariant(), clip.Rect(image.Rect(0, y, width, y+height)).Op())
+			}
+			off := op.Offset(image.Pt(0, y)).Push(gtx.Ops)
+			call.Add(gtx.Ops)
+			off.Pop()
+			y += height
+			paint.FillShape(gtx.Ops, t.Colors.OutlineVariant(), clip.Rect(image.Rect(0, y, width, y+line)).Op())
+			y += line
+		}
+		return D{Size: image.Pt(width, y)}
+	}
+}
+
+// imageBlock lays out an image block scaled down to fit the width, or its alternative
+// text until the image has loaded
+func (m *Markdown) imageBlock(b *mdBlock) W {
+	alt := m.inline(m.richText(b.alt, m.theme.typography().BodyMedium, Span{Italic: true}))
+	return func(gtx C) D {
+		if m.loadImage == nil {
+			return alt(gtx)
+		}
+		return m.image(b.text, alt).Layout(gtx)
+	}
+}
+
+// image returns the loading widget for a source, creating it on first use
+func (m *Markdown) image(src string, alt W) *Async[paint.ImageOp] {
+	if a, ok := m.images[src]; ok {
+		return a
+	}
+	load := m.loadImage
+	a := NewAsync(m.theme, func(ctx context.Context) (paint.ImageOp, error) {
+		img, err := load(src)
+		if err != nil {
+			return paint.ImageOp{}, err
+		}
+		if img == nil {
+			return paint.ImageOp{}, fmt.Errorf("image %q: no image", src)
+		}
+		return paint.NewImageOp(img), nil
+	}).
+		Loading(alt).
+		Error(func(gtx C, err error) D { return alt(gtx) }).
+		Success(layoutImage)
+	m.images[src] = a
+	return a
+}
+
+// layoutImage draws an image scaled down to fit the width
+func layoutImage(gtx C, img paint.ImageOp) D {
+	size := img.Size()
+	scale := float32(1)
+	if size.X > gtx.Constraints.Max.X && size.X > 0 {
+		scale = float32(gtx.Constraints.Max.X) / float32(size.X)
+	}
+	tr := op.Affine(f32.NewAffine2D(scale, 0, 0, 0, scale, 0)).Push(gtx.Ops)
+	area := clip.Rect{Max: size}.Push(gtx.Ops)
+	img.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	area.Pop()
+	tr.Pop()
+	return D{Size: image.Pt(int(float32(size.X)*scale), int(float32(size.Y)*scale))}
+}
+
+// clamp01 limits a value to the range 0 to 1
+func clamp01(v float32) float32 {
+	return maxFloat32(0, minFloat32(v, 1))
+}
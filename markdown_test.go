@@ -0,0 +1,170 @@
+package fromage
+
+import (
+	"image"
+	"testing"
+
+	"gio.mleku.dev/text"
+)
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	src := "# Title #\n\nSome *text*\nacross lines.\n\nSub\n---\n\n" +
+		"```go\nfmt.Println(1)\n```\n\n    indented\n\n> quoted\ncontinued\n\n***\n\n" +
+		"![logo](logo.png)\n"
+	blocks := parseMarkdown(src)
+	kinds := []mdKind{mdHeading, mdParagraph, mdHeading, mdCode, mdCode, mdQuote, mdRule, mdImage}
+	if len(blocks) != len(kinds) {
+		t.Fatalf("Expected %d blocks, got %d", len(kinds), len(blocks))
+	}
+	for i, b := range blocks {
+		if b.kind != kinds[i] {
+			t.Errorf("Block %d: expected kind %d, got %d", i, kinds[i], b.kind)
+		}
+	}
+	if blocks[0].level != 1 || blocks[0].text != "Title" {
+		t.Errorf("Unexpected heading %+v", blocks[0])
+	}
+	if blocks[2].level != 2 || blocks[2].text != "Sub" {
+		t.Errorf("Expected a setext level 2 heading, got %+v", blocks[2])
+	}
+	if blocks[3].lang != "go" || blocks[3].text != "fmt.Println(1)" {
+		t.Errorf("Unexpected fenced code %+v", blocks[3])
+	}
+	if blocks[4].text != "indented" {
+		t.Errorf("Unexpected indented code %q", blocks[4].text)
+	}
+	if q := blocks[5].children; len(q) != 1 || q[0].text != "quoted\ncontinued" {
+		t.Errorf("Expected a lazily continued quote paragraph, got %+v", q)
+	}
+	if blocks[7].text != "logo.png" || blocks[7].alt != "logo" {
+		t.Errorf("Unexpected image %+v", blocks[7])
+	}
+}
+
+func TestParseMarkdownLists(t *testing.T) {
+	blocks := parseMarkdown("3. one\n4. two\n   - nested\n   - more\n\n- other list")
+	if len(blocks) != 2 {
+		t.Fatalf("Expected two lists, got %d blocks", len(blocks))
+	}
+	list := blocks[0]
+	if !list.ordered || list.start != 3 || len(list.items) != 2 {
+		t.Fatalf("Unexpected list %+v", list)
+	}
+	second := list.items[1]
+	if len(second) != 2 || second[1].kind != mdList || len(second[1].items) != 2 {
+		t.Errorf("Expected a nested list in the second item, got %+v", second)
+	}
+	if blocks[1].ordered || len(blocks[1].items) != 1 {
+		t.Errorf("Unexpected bullet list %+v", blocks[1])
+	}
+}
+
+func TestParseMarkdownTable(t *testing.T) {
+	blocks := parseMarkdown("| Name | Size |  |\n|:-----|-----:|:-:|\n| a \\| b | 1 |\n")
+	if len(blocks) != 1 || blocks[0].kind != mdTable {
+		t.Fatalf("Expected a table, got %+v", blocks)
+	}
+	table := blocks[0]
+	if want := []text.Alignment{text.Start, text.End, text.Middle}; len(table.align) != 3 ||
+		table.align[0] != want[0] || table.align[1] != want[1] || table.align[2] != want[2] {
+		t.Errorf("Unexpected alignments %v", table.align)
+	}
+	if len(table.rows) != 2 || table.rows[1][0] != "a | b" || table.rows[1][2] != "" {
+		t.Errorf("Unexpected rows %q", table.rows)
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	spans := parseInline("a **bold *both*** `x` [link *it*](http://x \"t\") <https://y> \\*no\\*", Span{})
+	type want struct {
+		text         string
+		bold, italic bool
+		mono         bool
+		link         string
+	}
+	wants := []want{
+		{text: "a "},
+		{text: "bold ", bold: true},
+		{text: "both", bold: true, italic: true},
+		{text: " "},
+		{text: "x", mono: true},
+		{text: " "},
+		{text: "link ", link: "http://x"},
+		{text: "it", italic: true, link: "http://x"},
+		{text: " "},
+		{text: "https://y", link: "https://y"},
+		{text: " *no*"},
+	}
+	if len(spans) != len(wants) {
+		t.Fatalf("Expected %d spans, got %d: %+v", len(wants), len(spans), spans)
+	}
+	for i, s := range spans {
+		w := wants[i]
+		if s.Text != w.text || s.Bold != w.bold || s.Italic != w.italic || s.Monospace != w.mono || s.Link != w.link {
+			t.Errorf("Span %d: got %+v, want %+v", i, s, w)
+		}
+	}
+}
+
+func TestParseInlineLiterals(t *testing.T) {
+	for src, want := range map[string]string{
+		"snake_case_name": "snake_case_name",
+		"2 * 3 * 4":       "2 * 3 * 4",
+		"line  \nbreak":   "line\nbreak",
+		"soft\nwrap":      "soft wrap",
+		"[not a link]":    "[not a link]",
+	} {
+		var got string
+		for _, s := range parseInline(src, Span{}) {
+			got += s.Text
+		}
+		if got != want {
+			t.Errorf("parseInline(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestMarkdownBuild(t *testing.T) {
+	th := &Theme{Colors: NewColors()}
+	var clicked string
+	md := th.NewMarkdown("# Hi\n\nSee [docs](https://docs)\n\n- a\n- b\n\n|x|\n|-|\n|1|").
+		OnLink(func(link string) { clicked = link })
+	if len(md.widgets) != 4 {
+		t.Fatalf("Expected 4 block widgets, got %d", len(md.widgets))
+	}
+	// The rich text's callback forwards to the markdown's
+	rt := md.richText("[x](dest)", th.typography().BodyLarge, Span{})
+	rt.onLink(rt.spans[0].Link)
+	if clicked != "dest" {
+		t.Errorf("Expected link callback with dest, got %q", clicked)
+	}
+	if md.Source("plain").Text() != "plain" || len(md.widgets) != 1 {
+		t.Error("Expected Source to rebuild the blocks")
+	}
+}
+
+func TestMarkdownImageLoadsAsync(t *testing.T) {
+	th, ready := newAsyncTestTheme(t)
+	release := make(chan struct{})
+	loads := 0
+	md := th.NewMarkdown("![a logo](logo.png)").Images(func(src string) (image.Image, error) {
+		<-release
+		loads++
+		return image.NewRGBA(image.Rect(0, 0, 40, 30)), nil
+	})
+	gtx := asyncTestContext()
+	gtx.Constraints.Min = image.Point{}
+	// The loader is still blocked, so the first layout shows the alternative text
+	if d := md.widgets[0](gtx); d.Size.Y == 30 || d.Size.Y == 0 {
+		t.Errorf("Expected the alternative text while loading, got %v", d.Size)
+	}
+	close(release)
+	awaitFrame(t, th, ready)
+	if d := md.widgets[0](gtx); d.Size != image.Pt(40, 30) {
+		t.Errorf("Expected the loaded image, got %v", d.Size)
+	}
+	md.widgets[0](gtx)
+	if loads != 1 {
+		t.Errorf("Expected one load per source, got %d", loads)
+	}
+}
@@ -0,0 +1,556 @@
+package fromage
+
+import (
+	"strconv"
+	"strings"
+
+	"gio.mleku.dev/text"
+)
+
+// mdKind is the kind of a markdown block
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdTable
+	mdRule
+	mdImage
+)
+
+// mdBlock is a parsed markdown block
+type mdBlock struct {
+	kind mdKind
+	// Heading level from 1 to 6
+	level int
+	// Inline source of paragraphs and headings, the content of code blocks, or an image source
+	text string
+	// Alternative text of an image
+	alt string
+	// Info string of a fenced code block
+	lang string
+	// Whether a list is numbered, and its first number
+	ordered bool
+	start   int
+	// Blocks of each list item
+	items [][]*mdBlock
+	// Blocks inside a block quote
+	children []*mdBlock
+	// Table cells by row, header first, with the column alignments
+	rows  [][]string
+	align []text.Alignment
+}
+
+// parseMarkdown parses the block structure of a CommonMark document. It covers ATX and
+// setext headings, paragraphs, emphasis, code spans, fenced and indented code, block
+// quotes, nested lists, thematic breaks, inline links and images, autolinks and GitHub
+// tables. Reference-style links and raw HTML are left as text.
+func parseMarkdown(src string) []*mdBlock {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	return parseBlocks(strings.Split(src, "\n"))
+}
+
+// parseBlocks parses lines into blocks
+func parseBlocks(lines []string) []*mdBlock {
+	var blocks []*mdBlock
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, paragraphBlock(strings.Join(para, "\n")))
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		indent := indentOf(line)
+		switch {
+		case trimmed == "":
+			flush()
+			i++
+		case indent >= 4 && len(para) == 0:
+			// Indented code runs until a non-blank line with less indentation
+			var code []string
+			for ; i < len(lines) && (strings.TrimSpace(lines[i]) == "" || indentOf(lines[i]) >= 4); i++ {
+				code = append(code, stripIndent(lines[i], 4))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, &mdBlock{kind: mdCode, text: strings.Join(code, "\n")})
+		case indent < 4 && isFence(trimmed):
+			flush()
+			fence := trimmed[:fenceLen(trimmed)]
+			var code []string
+			for i++; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, stripIndent(lines[i], indent))
+			}
+			blocks = append(blocks, &mdBlock{
+				kind: mdCode,
+				text: strings.Join(code, "\n"),
+				lang: strings.TrimSpace(trimmed[len(fence):]),
+			})
+		case indent < 4 && headingLevel(trimmed) > 0:
+			flush()
+			level := headingLevel(trimmed)
+			content := strings.TrimSpace(trimmed[level:])
+			// Drop an optional closing sequence of #s
+			if closed := strings.TrimRight(content, "#"); closed == "" || strings.HasSuffix(closed, " ") {
+				content = strings.TrimSpace(closed)
+			}
+			blocks = append(blocks, &mdBlock{kind: mdHeading, level: level, text: content})
+			i++
+		case indent < 4 && len(para) > 0 && isSetextUnderline(trimmed):
+			level := 1
+			if trimmed[0] == '-' {
+				level = 2
+			}
+			blocks = append(blocks, &mdBlock{kind: mdHeading, level: level, text: strings.Join(para, "\n")})
+			para = nil
+			i++
+		case indent < 4 && isThematicBreak(trimmed):
+			flush()
+			blocks = append(blocks, &mdBlock{kind: mdRule})
+			i++
+		case indent < 4 && strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				t := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(t, ">") {
+					t = strings.TrimPrefix(t[1:], " ")
+				} else if t == "" || len(quoted) == 0 || startsBlock(t) {
+					// Only paragraph text continues a quote lazily
+					break
+				}
+				quoted = append(quoted, t)
+			}
+			blocks = append(blocks, &mdBlock{kind: mdQuote, children: parseBlocks(quoted)})
+		case indent < 4 && listMarker(trimmed) > 0:
+			flush()
+			var list *mdBlock
+			list, i = parseList(lines, i)
+			blocks = append(blocks, list)
+		case indent < 4 && len(para) == 0 && i+1 < len(lines) && strings.Contains(trimmed, "|") &&
+			isTableDelimiter(strings.TrimSpace(lines[i+1])):
+			var table *mdBlock
+			table, i = parseTable(lines, i)
+			blocks = append(blocks, table)
+		default:
+			para = append(para, trimmed)
+			i++
+		}
+	}
+	flush()
+	return blocks
+}
+
+// paragraphBlock returns a paragraph, or an image block when it holds only an image
+func paragraphBlock(text string) *mdBlock {
+	if strings.HasPrefix(text, "![") {
+		if alt, src, end, ok := parseLinkAt(text, 1); ok && end == len(text) {
+			return &mdBlock{kind: mdImage, text: src, alt: alt}
+		}
+	}
+	return &mdBlock{kind: mdParagraph, text: text}
+}
+
+// parseList parses the list starting at line i and returns it with the next line index
+func parseList(lines []string, i int) (*mdBlock, int) {
+	first := strings.TrimSpace(lines[i])
+	list := &mdBlock{kind: mdList, ordered: first[0] >= '0' && first[0] <= '9', start: 1}
+	if list.ordered {
+		list.start, _ = strconv.Atoi(first[:strings.IndexAny(first, ".)")])
+	}
+	delim := first[listMarker(first)-2]
+	base := indentOf(lines[i])
+	for i < len(lines) {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		marker := listMarker(trimmed)
+		// A new item needs the same kind of marker at the list's indentation
+		if marker == 0 || indentOf(line) > base+3 || trimmed[marker-2] != delim {
+			break
+		}
+		width := indentOf(line) + marker
+		item := []string{strings.TrimSpace(trimmed[marker:])}
+		blank := false
+		for i++; i < len(lines); i++ {
+			l := lines[i]
+			t := strings.TrimSpace(l)
+			switch {
+			case t == "":
+				blank = true
+				item = append(item, "")
+				continue
+			case indentOf(l) >= width:
+				blank = false
+				item = append(item, stripIndent(l, width))
+				continue
+			case !blank && !startsBlock(t):
+				// Lazy continuation of the item's paragraph
+				item = append(item, t)
+				continue
+			}
+			break
+		}
+		list.items = append(list.items, parseBlocks(item))
+		if i < len(lines) && strings.TrimSpace(lines[i]) != "" && listMarker(strings.TrimSpace(lines[i])) == 0 {
+			break
+		}
+	}
+	return list, i
+}
+
+// parseTable parses the table starting at line i and returns it with the next line index
+func parseTable(lines []string, i int) (*mdBlock, int) {
+	table := &mdBlock{kind: mdTable, rows: [][]string{splitTableRow(lines[i])}}
+	for _, cell := range splitTableRow(lines[i+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			table.align = append(table.align, text.Middle)
+		case right:
+			table.align = append(table.align, text.End)
+		default:
+			table.align = append(table.align, text.Start)
+		}
+	}
+	cols := len(table.rows[0])
+	for i += 2; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if t == "" || !strings.Contains(t, "|") {
+			break
+		}
+		row := splitTableRow(t)
+		// Rows are padded or cut to the header's width
+		for len(row) < cols {
+			row = append(row, "")
+		}
+		table.rows = append(table.rows, row[:cols])
+	}
+	for len(table.align) < cols {
+		table.align = append(table.align, text.Start)
+	}
+	table.align = table.align[:cols]
+	return table, i
+}
+
+// splitTableRow splits a table row into trimmed cells, honouring escaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// isTableDelimiter reports whether a line is a table's header delimiter row
+func isTableDelimiter(line string) bool {
+	if !strings.Contains(line, "-") {
+		return false
+	}
+	for _, cell := range splitTableRow(line) {
+		cell = strings.Trim(cell, ":")
+		if cell == "" || strings.Trim(cell, "-") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// indentOf returns the number of leading spaces of a line
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// stripIndent removes up to n leading spaces
+func stripIndent(line string, n int) string {
+	return line[min(n, indentOf(line)):]
+}
+
+// headingLevel returns the level of an ATX heading, or 0
+func headingLevel(line string) int {
+	n := len(line) - len(strings.TrimLeft(line, "#"))
+	if n < 1 || n > 6 || (len(line) > n && line[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// fenceLen returns the length of a code fence at the start of a line, or 0
+func fenceLen(line string) int {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return 0
+	}
+	n := len(line) - len(strings.TrimLeft(line, line[:1]))
+	// Backtick fences may not have backticks in their info string
+	if n < 3 || (line[0] == '`' && strings.Contains(line[n:], "`")) {
+		return 0
+	}
+	return n
+}
+
+// isFence reports whether a line opens a code fence
+func isFence(line string) bool { return fenceLen(line) > 0 }
+
+// isSetextUnderline reports whether a line underlines a setext heading
+func isSetextUnderline(line string) bool {
+	return line != "" && (strings.Trim(line, "=") == "" || strings.Trim(line, "-") == "")
+}
+
+// isThematicBreak reports whether a line is a thematic break
+func isThematicBreak(line string) bool {
+	s := strings.ReplaceAll(line, " ", "")
+	return len(s) >= 3 && (s[0] == '-' || s[0] == '*' || s[0] == '_') && strings.Trim(s, s[:1]) == ""
+}
+
+// listMarker returns the length of a list marker and its following space, or 0
+func listMarker(line string) int {
+	if len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ' {
+		return 2
+	}
+	n := 0
+	for n < len(line) && n < 9 && line[n] >= '0' && line[n] <= '9' {
+		n++
+	}
+	if n > 0 && n+1 < len(line) && (line[n] == '.' || line[n] == ')') && line[n+1] == ' ' {
+		return n + 2
+	}
+	return 0
+}
+
+// startsBlock reports whether a line starts a block that interrupts a paragraph
+func startsBlock(line string) bool {
+	return headingLevel(line) > 0 || isFence(line) || isThematicBreak(line) ||
+		strings.HasPrefix(line, ">") || listMarker(line) > 0
+}
+
+// Inline parsing
+
+// parseInline converts inline markdown into spans, deriving each span's style from base
+func parseInline(s string, base Span) []Span {
+	var spans []Span
+	var buf strings.Builder
+	emit := func() {
+		if buf.Len() > 0 {
+			span := base
+			span.Text = buf.String()
+			spans = append(spans, span)
+			buf.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			buf.WriteByte('\n')
+			i += 2
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			buf.WriteByte(s[i+1])
+			i += 2
+		case c == '\n':
+			// Two trailing spaces make a hard break, otherwise lines are joined
+			text := buf.String()
+			if strings.HasSuffix(text, "  ") {
+				buf.Reset()
+				buf.WriteString(strings.TrimRight(text, " ") + "\n")
+			} else {
+				buf.Reset()
+				buf.WriteString(strings.TrimRight(text, " ") + " ")
+			}
+			i++
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			end := closingRun(s, i+n, '`', n)
+			if end < 0 {
+				buf.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			code := strings.ReplaceAll(s[i+n:end], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			emit()
+			span := base
+			span.Text, span.Monospace = code, true
+			spans = append(spans, span)
+			i = end + n
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			alt, _, end, ok := parseLinkAt(s, i+1)
+			if !ok {
+				buf.WriteByte(c)
+				i++
+				continue
+			}
+			// Inline images are shown as their alternative text
+			emit()
+			img := base
+			img.Italic = true
+			spans = append(spans, parseInline(alt, img)...)
+			i = end
+		case c == '[':
+			label, dest, end, ok := parseLinkAt(s, i)
+			if !ok {
+				buf.WriteByte(c)
+				i++
+				continue
+			}
+			emit()
+			link := base
+			link.Link = dest
+			spans = append(spans, parseInline(label, link)...)
+			i = end
+		case c == '<':
+			end := strings.IndexByte(s[i:], '>')
+			dest := ""
+			if end > 0 {
+				dest = s[i+1 : i+end]
+			}
+			if dest == "" || strings.ContainsAny(dest, " <\n") ||
+				(!strings.Contains(dest, ":") && !strings.Contains(dest, "@")) {
+				buf.WriteByte(c)
+				i++
+				continue
+			}
+			emit()
+			link := base
+			link.Text, link.Link = dest, dest
+			if !strings.Contains(dest, ":") {
+				link.Link = "mailto:" + dest
+			}
+			spans = append(spans, link)
+			i += end + 1
+		case c == '*' || c == '_':
+			n := min(len(s[i:])-len(strings.TrimLeft(s[i:], string(c))), 3)
+			end := closingRun(s, i+n, c, n)
+			// Underscores inside words and delimiters followed by space are literal
+			intraword := c == '_' && i > 0 && isWordByte(s[i-1])
+			if end < 0 || end == i+n || intraword || s[i+n] == ' ' || s[end-1] == ' ' {
+				buf.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			emit()
+			styled := base
+			styled.Italic = styled.Italic || n != 2
+			styled.Bold = styled.Bold || n >= 2
+			spans = append(spans, parseInline(s[i+n:end], styled)...)
+			i = end + n
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	emit()
+	return spans
+}
+
+// closingRun returns the index of the closing n delimiter bytes from i, or -1. A run of
+// exactly n closes, as does the end of a longer run that ends a word, such as the "***"
+// closing both spans of "**bold *both***". Code spans only close on an exact run.
+func closingRun(s string, i int, delim byte, n int) int {
+	for i < len(s) {
+		if s[i] == '\\' && delim != '`' {
+			i += 2
+			continue
+		}
+		if s[i] != delim {
+			i++
+			continue
+		}
+		run := len(s[i:]) - len(strings.TrimLeft(s[i:], string(delim)))
+		if run == n {
+			return i
+		}
+		if run > n && delim != '`' && (i+run == len(s) || !isWordByte(s[i+run])) {
+			return i + run - n
+		}
+		i += run
+	}
+	return -1
+}
+
+// parseLinkAt parses a "[label](destination "title")" starting at the bracket at i,
+// returning the label, destination and the index after the link
+func parseLinkAt(s string, i int) (label, dest string, end int, ok bool) {
+	depth := 0
+	close := -1
+	for j := i; j < len(s) && close < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				close = j
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return "", "", 0, false
+	}
+	depth = 0
+	paren := -1
+	for j := close + 1; j < len(s) && paren < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				paren = j
+			}
+		}
+	}
+	if paren < 0 {
+		return "", "", 0, false
+	}
+	dest = strings.TrimSpace(s[close+2 : paren])
+	if strings.HasPrefix(dest, "<") {
+		if gt := strings.IndexByte(dest, '>'); gt > 0 {
+			dest = dest[1:gt]
+		}
+	} else if sp := strings.IndexAny(dest, " \n"); sp >= 0 {
+		// Drop the title
+		dest = dest[:sp]
+	}
+	return s[i+1 : close], dest, paren + 1, true
+}
+
+// isASCIIPunct reports whether a byte can be backslash-escaped
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// isWordByte reports whether a byte is a letter or digit
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
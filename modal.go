@@ -29,6 +29,7 @@ type Modal struct {
 	isAnimating       bool      // Whether animation is in progress
 	animationStarted  bool      // Whether animation has ever been started
 	isFadingOut       bool      // Whether we're fading out (true) or fading in (false)
+	fadeOutPending    bool      // Whether the next frame starts the fade-out
 }
 
 // NewModalStack creates a new modal stack
@@ -206,13 +207,14 @@ func (m *Modal) startAnimation(now time.Time) {
 	m.isAnimating = true
 	m.animationStarted = true
 	m.isFadingOut = false
+	m.fadeOutPending = false
 }
 
 // startFadeOut begins the fade-out animation
 func (m *Modal) startFadeOut() {
-	m.animationStart = time.Now()
 	m.isAnimating = true
 	m.isFadingOut = true
+	m.fadeOutPending = true
 }
 
 // updateAnimation updates the animation progress
@@ -221,10 +223,14 @@ func (m *Modal) updateAnimation(g C) {
 		return
 	}
 
-	const animationDuration = 250 * time.Millisecond
-	elapsed := g.Now.Sub(m.animationStart)
+	if m.fadeOutPending {
+		m.animationStart = g.Now
+		m.fadeOutPending = false
+	}
+	motion := m.theme.motion()
+	easedProgress, done := motion.Progress(m.animationStart, g.Now, motion.Medium, motion.Standard)
 
-	if elapsed >= animationDuration {
+	if done {
 		if m.isFadingOut {
 			m.animationProgress = 0.0
 			m.isAnimating = false
@@ -235,14 +241,6 @@ func (m *Modal) updateAnimation(g C) {
 		return
 	}
 
-	progress := float32(elapsed) / float32(animationDuration)
-	if progress > 1.0 {
-		progress = 1.0
-	}
-
-	// Use ease-out curve: 1 - (1-t)^2
-	easedProgress := 1.0 - (1.0-progress)*(1.0-progress)
-
 	if m.isFadingOut {
 		m.animationProgress = 1.0 - easedProgress
 	} else {
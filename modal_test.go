@@ -166,7 +166,7 @@ func TestModalFadeOut(t *testing.T) {
 
 	// Start fade-out
 	modal.startFadeOut()
-	gtx.Now = time.Now()
+	modal.updateAnimation(gtx)
 
 	if !modal.isAnimating {
 		t.Error("Expected modal to be animating during fade-out")
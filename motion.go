@@ -0,0 +1,135 @@
+package fromage
+
+import (
+	"time"
+)
+
+// Easing maps linear animation progress (0.0 to 1.0) to eased progress
+type Easing func(t float32) float32
+
+// Standard easing curves
+var (
+	// EaseLinear applies no easing
+	EaseLinear Easing = func(t float32) float32 { return t }
+	// EaseOutQuad decelerates towards the end: 1 - (1-t)^2
+	EaseOutQuad Easing = func(t float32) float32 { return 1 - (1-t)*(1-t) }
+	// EaseOutCubic decelerates more strongly towards the end: 1 - (1-t)^3
+	EaseOutCubic Easing = func(t float32) float32 { return 1 - (1-t)*(1-t)*(1-t) }
+	// EaseInOutCubic accelerates at the start and decelerates at the end
+	EaseInOutCubic Easing = func(t float32) float32 {
+		if t < 0.5 {
+			return 4 * t * t * t
+		}
+		f := -2*t + 2
+		return 1 - f*f*f/2
+	}
+)
+
+// Motion holds the theme-wide animation timing tokens
+type Motion struct {
+	// Short is used for small, quick transitions such as jumps to an end position
+	Short time.Duration
+	// Medium is used for most component transitions (switches, fades)
+	Medium time.Duration
+	// Long is used for large surfaces entering or leaving the screen (drawers)
+	Long time.Duration
+	// ThemeTransition is the duration of the role color cross-fade on theme mode changes
+	ThemeTransition time.Duration
+	// Standard is the default easing for component transitions
+	Standard Easing
+	// Emphasized is the easing for large surfaces entering or leaving the screen
+	Emphasized Easing
+	// ReducedMotion makes every animation jump straight to its end state
+	ReducedMotion bool
+}
+
+// NewMotion creates motion settings with the default durations and easings
+func NewMotion() *Motion {
+	return &Motion{
+		Short:           100 * time.Millisecond,
+		Medium:          250 * time.Millisecond,
+		Long:            300 * time.Millisecond,
+		ThemeTransition: 250 * time.Millisecond,
+		Standard:        EaseOutQuad,
+		Emphasized:      EaseOutCubic,
+	}
+}
+
+// Durations sets the short, medium and long durations
+func (m *Motion) Durations(short, medium, long time.Duration) *Motion {
+	m.Short = short
+	m.Medium = medium
+	m.Long = long
+	return m
+}
+
+// Scale multiplies all durations by the given factor (below 1 is snappier, above 1 is slower)
+func (m *Motion) Scale(factor float32) *Motion {
+	m.Short = time.Duration(float32(m.Short) * factor)
+	m.Medium = time.Duration(float32(m.Medium) * factor)
+	m.Long = time.Duration(float32(m.Long) * factor)
+	return m
+}
+
+// Easings sets the standard and emphasized easing curves
+func (m *Motion) Easings(standard, emphasized Easing) *Motion {
+	m.Standard = standard
+	m.Emphasized = emphasized
+	return m
+}
+
+// Reduced sets whether animations should be skipped
+func (m *Motion) Reduced(reduced bool) *Motion {
+	m.ReducedMotion = reduced
+	return m
+}
+
+// Duration returns the effective duration for an animation, which is zero when motion is reduced
+func (m *Motion) Duration(d time.Duration) time.Duration {
+	if m.ReducedMotion {
+		return 0
+	}
+	return d
+}
+
+// Progress returns the eased progress of an animation that started at start and lasts for d,
+// and whether the animation has finished. Reduced motion always reports a finished animation.
+func (m *Motion) Progress(start, now time.Time, d time.Duration, easing Easing) (float32, bool) {
+	d = m.Duration(d)
+	elapsed := now.Sub(start)
+	if d <= 0 || elapsed >= d {
+		return 1, true
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	progress := float32(elapsed) / float32(d)
+	if easing != nil {
+		progress = easing(progress)
+	}
+	return progress, false
+}
+
+// Theme motion methods
+
+// motion returns the theme's motion settings, giving a theme created without them its
+// own defaults, so changing them never affects another theme
+func (t *Theme) motion() *Motion {
+	if t == nil {
+		return NewMotion()
+	}
+	if t.Motion == nil {
+		t.Motion = NewMotion()
+	}
+	return t.Motion
+}
+
+// SetReducedMotion enables or disables reduced motion for every animated widget
+func (t *Theme) SetReducedMotion(reduced bool) {
+	t.motion().ReducedMotion = reduced
+}
+
+// ReducedMotion returns whether animations are disabled
+func (t *Theme) ReducedMotion() bool {
+	return t.motion().ReducedMotion
+}
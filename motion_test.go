@@ -0,0 +1,133 @@
+package fromage
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/app"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+)
+
+func TestMotionDefaults(t *testing.T) {
+	m := NewMotion()
+
+	if m.Short != 100*time.Millisecond {
+		t.Errorf("Expected short duration 100ms, got %v", m.Short)
+	}
+	if m.Medium != 250*time.Millisecond {
+		t.Errorf("Expected medium duration 250ms, got %v", m.Medium)
+	}
+	if m.Long != 300*time.Millisecond {
+		t.Errorf("Expected long duration 300ms, got %v", m.Long)
+	}
+	if m.ReducedMotion {
+		t.Error("Expected reduced motion to be off by default")
+	}
+}
+
+func TestThemeMotionNotShared(t *testing.T) {
+	a, b := &Theme{}, &Theme{}
+	a.motion().Scale(2)
+	a.SetReducedMotion(true)
+	if b.ReducedMotion() || b.motion().Medium != NewMotion().Medium {
+		t.Error("Expected a theme without motion settings to get its own defaults")
+	}
+	if a.motion() != a.Motion || a.motion().Medium != 2*NewMotion().Medium {
+		t.Error("Expected changes to a theme's default motion to stick")
+	}
+}
+
+func TestMotionProgress(t *testing.T) {
+	m := NewMotion()
+	start := time.Now()
+
+	// Halfway through an ease-out quad animation should be at 0.75
+	progress, done := m.Progress(start, start.Add(125*time.Millisecond), m.Medium, m.Standard)
+	if done {
+		t.Error("Expected animation to still be running halfway through")
+	}
+	if progress < 0.74 || progress > 0.76 {
+		t.Errorf("Expected progress around 0.75, got %f", progress)
+	}
+
+	// Past the end the animation should be finished
+	progress, done = m.Progress(start, start.Add(300*time.Millisecond), m.Medium, m.Standard)
+	if !done || progress != 1.0 {
+		t.Errorf("Expected finished animation with progress 1.0, got %f (done=%v)", progress, done)
+	}
+}
+
+func TestMotionReduced(t *testing.T) {
+	m := NewMotion().Reduced(true)
+	start := time.Now()
+
+	if m.Duration(m.Long) != 0 {
+		t.Errorf("Expected zero duration with reduced motion, got %v", m.Duration(m.Long))
+	}
+
+	progress, done := m.Progress(start, start, m.Long, m.Emphasized)
+	if !done || progress != 1.0 {
+		t.Errorf("Expected reduced motion to jump to the end, got %f (done=%v)", progress, done)
+	}
+}
+
+func TestMotionScale(t *testing.T) {
+	m := NewMotion().Scale(0.5)
+
+	if m.Medium != 125*time.Millisecond {
+		t.Errorf("Expected scaled medium duration 125ms, got %v", m.Medium)
+	}
+}
+
+func TestReducedMotionWidgets(t *testing.T) {
+	th := NewThemeWithMode(context.TODO(), NewColors, text.NewShaper(), unit.Dp(16), ThemeModeLight)
+	th.SetReducedMotion(true)
+
+	if !th.ReducedMotion() {
+		t.Fatal("Expected theme to report reduced motion")
+	}
+
+	ops := &op.Ops{}
+	gtx := app.NewContext(ops, app.FrameEvent{})
+	gtx.Now = time.Now()
+
+	// Modal fade should complete on the first frame
+	ms := th.NewModalStack()
+	ms.Push(func(g C) D { return D{Size: image.Pt(100, 50)} }, func() {})
+	modal := ms.modals[0]
+	modal.startAnimation(gtx.Now)
+	modal.updateAnimation(gtx)
+	if modal.isAnimating || modal.animationProgress != 1.0 {
+		t.Errorf("Expected modal to jump to fully visible, got %f", modal.animationProgress)
+	}
+
+	// Switch thumb should complete on the first frame
+	b := th.NewBool(false)
+	b.value = true
+	b.startAnimation(gtx.Now)
+	b.updateAnimation(gtx)
+	if b.isAnimating || b.animationProgress != 1.0 {
+		t.Errorf("Expected switch thumb to jump to the on position, got %f", b.animationProgress)
+	}
+
+	// Drawer slide should complete on the first frame
+	w := &Window{Theme: th}
+	d := w.NewDrawer()
+	d.startAnimation(gtx.Now)
+	d.updateAnimation(gtx)
+	if d.isAnimating || d.animationProgress != 1.0 {
+		t.Errorf("Expected drawer to jump to fully open, got %f", d.animationProgress)
+	}
+
+	// Scrollbar track animation should complete on the first frame
+	sb := th.NewScrollbar(Vertical)
+	sb.startAnimation(0.5, gtx)
+	sb.updateAnimation(gtx)
+	if sb.animating || sb.Position() != 0.5 {
+		t.Errorf("Expected scrollbar to jump to 0.5, got %f", sb.Position())
+	}
+}
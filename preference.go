@@ -0,0 +1,195 @@
+package fromage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// ColorScheme is the system-wide light/dark preference
+type ColorScheme int
+
+const (
+	ColorSchemeNoPreference ColorScheme = iota
+	ColorSchemeDark
+	ColorSchemeLight
+)
+
+// ErrNoPreferenceSource is returned when the platform has no color scheme preference source
+var ErrNoPreferenceSource = errors.New("no system color scheme preference source available")
+
+// PreferenceSource reports the system color scheme preference used by ThemeModeAuto
+type PreferenceSource interface {
+	// ColorScheme returns the current system preference
+	ColorScheme(ctx context.Context) (ColorScheme, error)
+	// Watch delivers preference changes until ctx is done, then closes the channel
+	Watch(ctx context.Context) (<-chan ColorScheme, error)
+}
+
+// FakePreferenceSource is an in-process preference source for tests and for apps that
+// let the user pick the "system" preference themselves
+type FakePreferenceSource struct {
+	mx       sync.Mutex
+	scheme   ColorScheme
+	watchers []chan ColorScheme
+}
+
+// NewFakePreferenceSource creates a fake preference source with the given initial scheme
+func NewFakePreferenceSource(scheme ColorScheme) *FakePreferenceSource {
+	return &FakePreferenceSource{scheme: scheme}
+}
+
+// ColorScheme returns the current fake preference
+func (f *FakePreferenceSource) ColorScheme(ctx context.Context) (ColorScheme, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	return f.scheme, nil
+}
+
+// Watch delivers every subsequent Set until ctx is done
+func (f *FakePreferenceSource) Watch(ctx context.Context) (<-chan ColorScheme, error) {
+	ch := make(chan ColorScheme, 1)
+	f.mx.Lock()
+	f.watchers = append(f.watchers, ch)
+	f.mx.Unlock()
+	go func() {
+		<-ctx.Done()
+		f.mx.Lock()
+		defer f.mx.Unlock()
+		for i, w := range f.watchers {
+			if w == ch {
+				f.watchers = append(f.watchers[:i], f.watchers[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// Set changes the fake preference and notifies all watchers
+func (f *FakePreferenceSource) Set(scheme ColorScheme) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.scheme = scheme
+	for _, w := range f.watchers {
+		// Keep only the latest value if the watcher has not caught up
+		select {
+		case <-w:
+		default:
+		}
+		w <- scheme
+	}
+}
+
+// portalSchemeRe matches the uint32 value of an org.freedesktop.appearance color-scheme reply
+var portalSchemeRe = regexp.MustCompile(`uint32 (\d+)`)
+
+// portalChangedRe matches a SettingChanged signal for the color-scheme key
+var portalChangedRe = regexp.MustCompile(`'org\.freedesktop\.appearance', 'color-scheme', <uint32 (\d+)>`)
+
+// parsePortalColorScheme extracts the color scheme from a settings portal Read reply
+func parsePortalColorScheme(reply string) (ColorScheme, bool) {
+	m := portalSchemeRe.FindStringSubmatch(reply)
+	if m == nil {
+		return ColorSchemeNoPreference, false
+	}
+	return portalColorScheme(m[1])
+}
+
+// parsePortalSettingChanged extracts the color scheme from a monitored SettingChanged signal
+func parsePortalSettingChanged(line string) (ColorScheme, bool) {
+	m := portalChangedRe.FindStringSubmatch(line)
+	if m == nil {
+		return ColorSchemeNoPreference, false
+	}
+	return portalColorScheme(m[1])
+}
+
+// portalColorScheme maps the portal's 0/1/2 values onto ColorScheme
+func portalColorScheme(v string) (ColorScheme, bool) {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return ColorSchemeNoPreference, false
+	}
+	switch n {
+	case 1:
+		return ColorSchemeDark, true
+	case 2:
+		return ColorSchemeLight, true
+	default:
+		return ColorSchemeNoPreference, true
+	}
+}
+
+// Theme system preference methods
+
+// FollowSystem reads the system color scheme from src and keeps watching it, so that a
+// theme in ThemeModeAuto switches live. Changes are applied on the UI goroutine by
+// BeginFrame; onChange is called from the watcher goroutine so the caller can request a
+// new frame. Watching stops when the theme's context is done, or when FollowSystem is
+// called again.
+func (t *Theme) FollowSystem(src PreferenceSource, onChange func()) (err error) {
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if t.stopSystemWatch != nil {
+		t.stopSystemWatch()
+		t.stopSystemWatch = nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var scheme ColorScheme
+	if scheme, err = src.ColorScheme(ctx); err != nil {
+		cancel()
+		return
+	}
+	t.applySystemScheme(scheme)
+	var changes <-chan ColorScheme
+	if changes, err = src.Watch(ctx); err != nil {
+		cancel()
+		return
+	}
+	t.stopSystemWatch = cancel
+	pending := make(chan ColorScheme, 1)
+	t.systemSchemes = pending
+	go func() {
+		for scheme := range changes {
+			// Keep only the latest value if the UI has not drained it yet
+			select {
+			case <-pending:
+			default:
+			}
+			pending <- scheme
+			if onChange != nil {
+				onChange()
+			}
+		}
+	}()
+	return
+}
+
+// FollowSystemTheme makes the window's theme follow src, invalidating the window on changes
+func (w *Window) FollowSystemTheme(src PreferenceSource) error {
+	return w.Theme.FollowSystem(src, w.Invalidate)
+}
+
+// applySystemScheme records a system preference change with the theme's cross-fade
+func (t *Theme) applySystemScheme(scheme ColorScheme) {
+	t.applyTransition()
+	t.Colors.SetSystemColorScheme(scheme)
+}
+
+// drainSystemSchemes applies a pending system preference change, if any
+func (t *Theme) drainSystemSchemes() {
+	if t.systemSchemes == nil {
+		return
+	}
+	select {
+	case scheme := <-t.systemSchemes:
+		t.applySystemScheme(scheme)
+	default:
+	}
+}
@@ -0,0 +1,86 @@
+//go:build linux
+
+package fromage
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// PortalPreferenceSource reads the color scheme from the freedesktop settings portal
+// (org.freedesktop.portal.Settings) on the D-Bus session bus using the gdbus tool
+type PortalPreferenceSource struct {
+	// Command is the gdbus executable, defaults to "gdbus"
+	Command string
+}
+
+// DefaultPreferenceSource returns the platform's system color scheme preference source
+func DefaultPreferenceSource() (PreferenceSource, error) {
+	src := &PortalPreferenceSource{Command: "gdbus"}
+	if _, err := exec.LookPath(src.Command); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoPreferenceSource, err)
+	}
+	return src, nil
+}
+
+// ColorScheme reads the current org.freedesktop.appearance color-scheme setting
+func (p *PortalPreferenceSource) ColorScheme(ctx context.Context) (ColorScheme, error) {
+	out, err := exec.CommandContext(ctx, p.command(),
+		"call", "--session",
+		"--dest", "org.freedesktop.portal.Desktop",
+		"--object-path", "/org/freedesktop/portal/desktop",
+		"--method", "org.freedesktop.portal.Settings.Read",
+		"org.freedesktop.appearance", "color-scheme",
+	).Output()
+	if err != nil {
+		return ColorSchemeNoPreference, fmt.Errorf("reading color-scheme from settings portal: %w", err)
+	}
+	scheme, ok := parsePortalColorScheme(string(out))
+	if !ok {
+		return ColorSchemeNoPreference, fmt.Errorf("unexpected settings portal reply %q", out)
+	}
+	return scheme, nil
+}
+
+// Watch monitors the settings portal for color-scheme SettingChanged signals
+func (p *PortalPreferenceSource) Watch(ctx context.Context) (<-chan ColorScheme, error) {
+	cmd := exec.CommandContext(ctx, p.command(),
+		"monitor", "--session",
+		"--dest", "org.freedesktop.portal.Desktop",
+		"--object-path", "/org/freedesktop/portal/desktop",
+	)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("monitoring settings portal: %w", err)
+	}
+	ch := make(chan ColorScheme)
+	go func() {
+		defer close(ch)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			scheme, ok := parsePortalSettingChanged(scanner.Text())
+			if !ok {
+				continue
+			}
+			select {
+			case ch <- scheme:
+			case <-ctx.Done():
+			}
+		}
+		_ = cmd.Wait()
+	}()
+	return ch, nil
+}
+
+// command returns the gdbus executable to run
+func (p *PortalPreferenceSource) command() string {
+	if p.Command == "" {
+		return "gdbus"
+	}
+	return p.Command
+}
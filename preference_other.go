@@ -0,0 +1,8 @@
+//go:build !linux
+
+package fromage
+
+// DefaultPreferenceSource returns the platform's system color scheme preference source
+func DefaultPreferenceSource() (PreferenceSource, error) {
+	return nil, ErrNoPreferenceSource
+}
@@ -0,0 +1,126 @@
+package fromage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/unit"
+)
+
+func TestThemeModeAutoResolution(t *testing.T) {
+	light := NewColorsWithMode(ThemeModeLight)
+	dark := NewColorsWithMode(ThemeModeDark)
+
+	colors := NewColorsWithMode(ThemeModeAuto)
+	if colors.EffectiveMode() != ThemeModeLight {
+		t.Errorf("Expected auto mode without a preference to resolve to light, got %v", colors.EffectiveMode())
+	}
+
+	colors.SetSystemColorScheme(ColorSchemeDark)
+	if colors.ThemeMode() != ThemeModeAuto {
+		t.Errorf("Expected theme mode to stay auto, got %v", colors.ThemeMode())
+	}
+	if colors.EffectiveMode() != ThemeModeDark {
+		t.Errorf("Expected auto mode to resolve to dark, got %v", colors.EffectiveMode())
+	}
+	if colors.Background() != dark.Background() {
+		t.Errorf("Expected dark background %v, got %v", dark.Background(), colors.Background())
+	}
+
+	colors.SetSystemColorScheme(ColorSchemeLight)
+	if colors.Background() != light.Background() {
+		t.Errorf("Expected light background %v, got %v", light.Background(), colors.Background())
+	}
+
+	// An explicit mode ignores the system preference
+	explicit := NewColorsWithMode(ThemeModeLight)
+	explicit.SetSystemColorScheme(ColorSchemeDark)
+	if explicit.EffectiveMode() != ThemeModeLight {
+		t.Errorf("Expected explicit light mode to ignore system preference, got %v", explicit.EffectiveMode())
+	}
+}
+
+func TestThemeFollowSystem(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	theme := NewThemeWithMode(ctx, NewColors, nil, unit.Dp(16), ThemeModeAuto)
+	theme.SetReducedMotion(true)
+
+	src := NewFakePreferenceSource(ColorSchemeDark)
+	changed := make(chan struct{}, 1)
+	if err := theme.FollowSystem(src, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("FollowSystem failed: %v", err)
+	}
+	if !theme.IsDark() {
+		t.Error("Expected initial system preference to apply immediately")
+	}
+
+	src.Set(ColorSchemeLight)
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected onChange to be called after the preference changed")
+	}
+	if !theme.IsDark() {
+		t.Error("Expected the change to wait for the next frame")
+	}
+	theme.drainSystemSchemes()
+	if !theme.IsLight() {
+		t.Error("Expected theme to follow the system preference to light")
+	}
+}
+
+func TestThemeFollowSystemTwice(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	theme := NewThemeWithMode(ctx, NewColors, nil, unit.Dp(16), ThemeModeAuto)
+
+	src := NewFakePreferenceSource(ColorSchemeDark)
+	for range 2 {
+		if err := theme.FollowSystem(src, nil); err != nil {
+			t.Fatalf("FollowSystem failed: %v", err)
+		}
+	}
+	// The first watch ends once its context is cancelled
+	deadline := time.Now().Add(time.Second)
+	for {
+		src.mx.Lock()
+		n := len(src.watchers)
+		src.mx.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the second call to stop the first watch, got %d watchers", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestParsePortalColorScheme(t *testing.T) {
+	tests := []struct {
+		in     string
+		scheme ColorScheme
+		ok     bool
+	}{
+		{"(<<uint32 1>>,)\n", ColorSchemeDark, true},
+		{"(<<uint32 2>>,)\n", ColorSchemeLight, true},
+		{"(<<uint32 0>>,)\n", ColorSchemeNoPreference, true},
+		{"Error: GDBus.Error", ColorSchemeNoPreference, false},
+	}
+	for _, tt := range tests {
+		scheme, ok := parsePortalColorScheme(tt.in)
+		if scheme != tt.scheme || ok != tt.ok {
+			t.Errorf("parsePortalColorScheme(%q) = %v, %v; want %v, %v", tt.in, scheme, ok, tt.scheme, tt.ok)
+		}
+	}
+
+	signal := "/org/freedesktop/portal/desktop: org.freedesktop.portal.Settings.SettingChanged ('org.freedesktop.appearance', 'color-scheme', <uint32 1>)"
+	if scheme, ok := parsePortalSettingChanged(signal); !ok || scheme != ColorSchemeDark {
+		t.Errorf("Expected dark from SettingChanged signal, got %v, %v", scheme, ok)
+	}
+	if _, ok := parsePortalSettingChanged("('org.freedesktop.appearance', 'accent-color', <(1.0, 0.0, 0.0)>)"); ok {
+		t.Error("Expected unrelated setting to be ignored")
+	}
+}
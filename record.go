@@ -0,0 +1,424 @@
+package fromage
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"time"
+
+	"gio.mleku.dev/io/event"
+	"gio.mleku.dev/io/input"
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/io/pointer"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/unit"
+)
+
+// Recording is the input of a window session, frame by frame, for reproducing bugs
+type Recording struct {
+	Frames []RecordedFrame
+}
+
+// RecordedFrame is the input delivered in one frame
+type RecordedFrame struct {
+	// Time since the first frame
+	Time time.Duration
+	// Window size in pixels and density
+	Size   image.Point
+	Metric unit.Metric
+	// Pointer, key and text input events, in order
+	Events []event.Event
+}
+
+// recordingMagic starts every recording file, followed by the format version
+const recordingMagic = "fromage-rec"
+
+const recordingVersion = 1
+
+// maxRecordedText bounds the length of a recorded key name or edit, so a corrupt file
+// cannot make ReadRecording allocate without limit
+const maxRecordedText = 1 << 20
+
+// Record tags
+const (
+	recFrame     = 'F'
+	recSize      = 'S'
+	recPointer   = 'P'
+	recKey       = 'K'
+	recEdit      = 'E'
+	recSnippet   = 'N'
+	recSelection = 'L'
+)
+
+// inputProxy takes all the input of a window and delivers it to the widgets through a
+// router of its own. Laid out at the root of the window, it covers the whole window and
+// holds the window's keyboard focus, so no event reaches the widgets past it; the widgets
+// see its router instead, which handles hit testing, grabs and focus as the window's
+// would. Text typed into the window reaches the focused widget as edit events, but
+// clipboard and text input commands are not forwarded to the window, so an input method
+// does not see the widget's text.
+type inputProxy struct {
+	router input.Router
+}
+
+// capture returns the pointer, key and text input events the window delivered this frame
+func (p *inputProxy) capture(gtx C) []event.Event {
+	if !gtx.Focused(p) {
+		gtx.Execute(key.FocusCmd{Tag: p})
+	}
+	var events []event.Event
+	for {
+		ev, ok := gtx.Event(
+			pointer.Filter{
+				Target:  p,
+				Kinds:   pointer.Press | pointer.Release | pointer.Move | pointer.Drag | pointer.Scroll | pointer.Cancel,
+				ScrollX: pointer.ScrollRange{Min: math.MinInt32, Max: math.MaxInt32},
+				ScrollY: pointer.ScrollRange{Min: math.MinInt32, Max: math.MaxInt32},
+			},
+			key.FocusFilter{Target: p},
+			key.Filter{Focus: p, Optional: key.ModCtrl | key.ModCommand | key.ModShift | key.ModAlt | key.ModSuper},
+		)
+		if !ok {
+			break
+		}
+		switch ev.(type) {
+		case pointer.Event, key.Event, key.EditEvent, key.SnippetEvent, key.SelectionEvent:
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// layout lays out root with the events queued on the proxy's router, at the time now and
+// with the size and density of f
+func (p *inputProxy) layout(gtx C, f RecordedFrame, now time.Time, root W) D {
+	p.router.Queue(f.Events...)
+	inner := gtx
+	inner.Source = p.router.Source()
+	inner.Now = now
+	inner.Constraints = layout.Exact(f.Size)
+	inner.Metric = f.Metric
+	d := root(inner)
+	p.router.Frame(gtx.Ops)
+	// Cover the window on top of the widgets so every pointer event reaches the proxy
+	area := clip.Rect(image.Rectangle{Max: gtx.Constraints.Max}).Push(gtx.Ops)
+	event.Op(gtx.Ops, p)
+	p.router.Cursor().Add(gtx.Ops)
+	area.Pop()
+	if at, ok := p.router.WakeupTime(); ok {
+		gtx.Execute(op.InvalidateCmd{At: at})
+	}
+	return d
+}
+
+// Recorder writes the input of a window to a recording as the window runs
+type Recorder struct {
+	proxy inputProxy
+	out   *bufio.Writer
+	// Time of the first frame
+	start time.Time
+	// Size and density last written
+	size   image.Point
+	metric unit.Metric
+	// First write error; recording stops after it
+	err error
+	buf []byte
+}
+
+// Record starts recording the window's input to out. The recorder's Layout must wrap the
+// window's whole content in place of laying it out directly, and Flush must be called
+// before out is closed. While recording, clipboard and text input commands from the
+// widgets are not passed to the window.
+func (w *Window) Record(out io.Writer) *Recorder {
+	r := &Recorder{out: bufio.NewWriter(out)}
+	_, r.err = r.out.WriteString(recordingMagic)
+	if r.err == nil {
+		r.err = r.out.WriteByte(recordingVersion)
+	}
+	return r
+}
+
+// Layout records the frame's input and lays out root with it
+func (r *Recorder) Layout(gtx C, root W) D {
+	events := r.proxy.capture(gtx)
+	if r.start.IsZero() {
+		r.start = gtx.Now
+	}
+	f := RecordedFrame{Time: gtx.Now.Sub(r.start), Size: gtx.Constraints.Max, Metric: gtx.Metric, Events: events}
+	r.write(f)
+	return r.proxy.layout(gtx, f, gtx.Now, root)
+}
+
+// Flush writes buffered frames and returns the first error of the recording
+func (r *Recorder) Flush() error {
+	if r.err == nil {
+		r.err = r.out.Flush()
+	}
+	return r.err
+}
+
+// write encodes a frame
+func (r *Recorder) write(f RecordedFrame) {
+	if r.err != nil {
+		return
+	}
+	b := r.buf[:0]
+	if f.Size != r.size || f.Metric != r.metric {
+		r.size, r.metric = f.Size, f.Metric
+		b = append(b, recSize)
+		b = binary.AppendUvarint(b, uint64(f.Size.X))
+		b = binary.AppendUvarint(b, uint64(f.Size.Y))
+		b = appendFloat(b, f.Metric.PxPerDp)
+		b = appendFloat(b, f.Metric.PxPerSp)
+	}
+	b = append(b, recFrame)
+	b = binary.AppendUvarint(b, uint64(f.Time))
+	b = binary.AppendUvarint(b, uint64(len(f.Events)))
+	for _, ev := range f.Events {
+		switch e := ev.(type) {
+		case pointer.Event:
+			b = append(b, recPointer)
+			b = binary.AppendUvarint(b, uint64(e.Kind))
+			b = binary.AppendUvarint(b, uint64(e.Source))
+			b = binary.AppendUvarint(b, uint64(e.PointerID))
+			b = binary.AppendUvarint(b, uint64(e.Buttons))
+			b = binary.AppendUvarint(b, uint64(e.Modifiers))
+			b = binary.AppendUvarint(b, uint64(e.Time))
+			b = appendFloat(b, e.Position.X)
+			b = appendFloat(b, e.Position.Y)
+			b = appendFloat(b, e.Scroll.X)
+			b = appendFloat(b, e.Scroll.Y)
+		case key.Event:
+			b = append(b, recKey)
+			b = binary.AppendUvarint(b, uint64(len(e.Name)))
+			b = append(b, e.Name...)
+			b = binary.AppendUvarint(b, uint64(e.Modifiers))
+			b = binary.AppendUvarint(b, uint64(e.State))
+		case key.EditEvent:
+			b = append(b, recEdit)
+			b = appendRange(b, e.Range)
+			b = binary.AppendUvarint(b, uint64(len(e.Text)))
+			b = append(b, e.Text...)
+		case key.SnippetEvent:
+			b = append(b, recSnippet)
+			b = appendRange(b, key.Range(e))
+		case key.SelectionEvent:
+			b = append(b, recSelection)
+			b = appendRange(b, key.Range(e))
+		}
+	}
+	r.buf = b
+	_, r.err = r.out.Write(b)
+}
+
+// appendFloat appends the bits of a float32 as a varint
+func appendFloat(b []byte, f float32) []byte {
+	return binary.AppendUvarint(b, uint64(math.Float32bits(f)))
+}
+
+// appendRange appends the ends of a text range, which may be negative
+func appendRange(b []byte, r key.Range) []byte {
+	b = binary.AppendVarint(b, int64(r.Start))
+	return binary.AppendVarint(b, int64(r.End))
+}
+
+// ReadRecording decodes a recording written by a Recorder
+func ReadRecording(in io.Reader) (*Recording, error) {
+	r := recordingReader{r: bufio.NewReader(in)}
+	magic := make([]byte, len(recordingMagic)+1)
+	if _, err := io.ReadFull(r.r, magic); err != nil || string(magic[:len(recordingMagic)]) != recordingMagic {
+		return nil, errors.New("recording: not a recording")
+	}
+	if v := magic[len(recordingMagic)]; v != recordingVersion {
+		return nil, fmt.Errorf("recording: unsupported version %d", v)
+	}
+	rec := &Recording{}
+	var size image.Point
+	var metric unit.Metric
+	for {
+		tag, err := r.r.ReadByte()
+		if err == io.EOF {
+			return rec, nil
+		}
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("recording: %w", err)
+		case tag == recSize:
+			size = image.Pt(int(r.uvarint()), int(r.uvarint()))
+			metric = unit.Metric{PxPerDp: r.float(), PxPerSp: r.float()}
+		case tag == recFrame:
+			f := RecordedFrame{Time: time.Duration(r.uvarint()), Size: size, Metric: metric}
+			n := r.uvarint()
+			for i := uint64(0); i < n && r.err == nil; i++ {
+				f.Events = append(f.Events, r.event())
+			}
+			rec.Frames = append(rec.Frames, f)
+		default:
+			return nil, fmt.Errorf("recording: unknown record %q in frame %d", tag, len(rec.Frames))
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("recording: frame %d: %w", len(rec.Frames), r.err)
+		}
+	}
+}
+
+// recordingReader decodes the parts of a recording, keeping the first error
+type recordingReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *recordingReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	var v uint64
+	v, r.err = binary.ReadUvarint(r.r)
+	if r.err == io.EOF {
+		r.err = io.ErrUnexpectedEOF
+	}
+	return v
+}
+
+func (r *recordingReader) float() float32 {
+	return math.Float32frombits(uint32(r.uvarint()))
+}
+
+func (r *recordingReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	var v int64
+	v, r.err = binary.ReadVarint(r.r)
+	if r.err == io.EOF {
+		r.err = io.ErrUnexpectedEOF
+	}
+	return v
+}
+
+func (r *recordingReader) textRange() key.Range {
+	return key.Range{Start: int(r.varint()), End: int(r.varint())}
+}
+
+// text decodes a length, up to maxRecordedText, and that many bytes
+func (r *recordingReader) text() string {
+	n := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if n > maxRecordedText {
+		r.err = fmt.Errorf("text of %d bytes is over the %d byte limit", n, maxRecordedText)
+		return ""
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.err = io.ErrUnexpectedEOF
+		return ""
+	}
+	return string(b)
+}
+
+// event decodes a pointer, key or text input event
+func (r *recordingReader) event() event.Event {
+	tag, err := r.r.ReadByte()
+	if err != nil {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	switch tag {
+	case recPointer:
+		e := pointer.Event{
+			Kind:      pointer.Kind(r.uvarint()),
+			Source:    pointer.Source(r.uvarint()),
+			PointerID: pointer.ID(r.uvarint()),
+			Buttons:   pointer.Buttons(r.uvarint()),
+			Modifiers: key.Modifiers(r.uvarint()),
+			Time:      time.Duration(r.uvarint()),
+		}
+		e.Position.X, e.Position.Y = r.float(), r.float()
+		e.Scroll.X, e.Scroll.Y = r.float(), r.float()
+		return e
+	case recKey:
+		return key.Event{Name: key.Name(r.text()), Modifiers: key.Modifiers(r.uvarint()), State: key.State(r.uvarint())}
+	case recEdit:
+		rng := r.textRange()
+		return key.EditEvent{Range: rng, Text: r.text()}
+	case recSnippet:
+		return key.SnippetEvent(r.textRange())
+	case recSelection:
+		return key.SelectionEvent(r.textRange())
+	}
+	r.err = fmt.Errorf("unknown event %q", tag)
+	return nil
+}
+
+// Replayer feeds a recording to a window in place of its input
+type Replayer struct {
+	proxy  inputProxy
+	frames []RecordedFrame
+	next   int
+	// Time of the first replayed frame
+	start time.Time
+	// Callback when the last frame has been replayed
+	onDone func()
+}
+
+// Replay replaces the window's input with the recording, one recorded frame per frame.
+// The replayer's Layout must wrap the window's whole content, which it lays out at the
+// recorded size and density whatever the window's, so pointer events land where they
+// did. Live input is ignored until the replay finishes.
+func (w *Window) Replay(rec *Recording) *Replayer {
+	return &Replayer{frames: rec.Frames, onDone: func() {}}
+}
+
+// OnDone sets the callback run after the last frame is replayed
+func (p *Replayer) OnDone(fn func()) *Replayer {
+	p.onDone = fn
+	return p
+}
+
+// Done reports whether every frame has been replayed
+func (p *Replayer) Done() bool { return p.next >= len(p.frames) }
+
+// Layout lays out root with the next recorded frame's input at its recorded time, and
+// with live input once the replay is done
+func (p *Replayer) Layout(gtx C, root W) D {
+	events := p.proxy.capture(gtx)
+	if p.Done() {
+		return p.proxy.layout(gtx, RecordedFrame{Size: gtx.Constraints.Max, Metric: gtx.Metric, Events: events}, gtx.Now, root)
+	}
+	if p.start.IsZero() {
+		p.start = gtx.Now
+	}
+	f := p.frames[p.next]
+	p.next++
+	if f.Size == (image.Point{}) {
+		f.Size, f.Metric = gtx.Constraints.Max, gtx.Metric
+	}
+	d := p.proxy.layout(gtx, f, p.start.Add(f.Time), root)
+	if p.Done() {
+		p.onDone()
+	} else {
+		gtx.Execute(op.InvalidateCmd{})
+	}
+	return d
+}
+
+// Replay lays out every frame of the recording with its size, density, time and input
+func (d *Driver) Replay(rec *Recording) D {
+	for _, f := range rec.Frames {
+		if f.Size != (image.Point{}) {
+			d.size, d.metric = f.Size, f.Metric
+		}
+		d.now = d.start.Add(f.Time)
+		d.Queue(f.Events...)
+		d.Frame()
+	}
+	return d.dims
+}
@@ -0,0 +1,176 @@
+package fromage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/f32"
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/io/event"
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/io/pointer"
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/unit"
+	"gio.mleku.dev/widget"
+)
+
+var testRecording = &Recording{Frames: []RecordedFrame{
+	{Size: image.Pt(320, 240), Metric: unit.Metric{PxPerDp: 1.5, PxPerSp: 1.5}},
+	{Time: 16 * time.Millisecond, Size: image.Pt(320, 240), Metric: unit.Metric{PxPerDp: 1.5, PxPerSp: 1.5}, Events: []event.Event{
+		pointer.Event{Kind: pointer.Press, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary,
+			Position: f32.Pt(10.5, 20.25), Time: 15 * time.Millisecond},
+		pointer.Event{Kind: pointer.Scroll, Source: pointer.Mouse, Position: f32.Pt(10.5, 20.25),
+			Scroll: f32.Pt(0, -42), Modifiers: key.ModShift},
+		key.Event{Name: key.NameEscape, State: key.Press},
+		key.Event{Name: "C", Modifiers: key.ModShortcut, State: key.Release},
+		key.EditEvent{Range: key.Range{Start: 2, End: 4}, Text: "héllo"},
+		key.SnippetEvent{Start: 0, End: -1},
+		key.SelectionEvent{Start: 3, End: 3},
+	}},
+	{Time: 400 * time.Millisecond, Size: image.Pt(640, 480), Metric: unit.Metric{PxPerDp: 2, PxPerSp: 2}},
+}}
+
+func encodeRecording(t *testing.T, rec *Recording) []byte {
+	var buf bytes.Buffer
+	r := (&Window{}).Record(&buf)
+	for _, f := range rec.Frames {
+		r.write(f)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	data := encodeRecording(t, testRecording)
+	got, err := ReadRecording(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, testRecording) {
+		t.Errorf("Expected the recording back, got %+v", got)
+	}
+	if len(data) > 140 {
+		t.Errorf("Expected a compact encoding, got %d bytes", len(data))
+	}
+}
+
+func TestReadRecordingErrors(t *testing.T) {
+	data := encodeRecording(t, testRecording)
+	if _, err := ReadRecording(bytes.NewReader([]byte("not a recording"))); err == nil {
+		t.Error("Expected other data to be rejected")
+	}
+	newer := append([]byte(nil), data...)
+	newer[len(recordingMagic)] = recordingVersion + 1
+	if _, err := ReadRecording(bytes.NewReader(newer)); err == nil {
+		t.Error("Expected a newer version to be rejected")
+	}
+	if _, err := ReadRecording(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Error("Expected a truncated recording to be rejected")
+	}
+	// A frame with one key event whose name claims far more bytes than the limit
+	huge := []byte(recordingMagic)
+	huge = append(huge, recordingVersion, recFrame, 0, 1, recKey)
+	huge = binary.AppendUvarint(huge, 1<<62)
+	if _, err := ReadRecording(bytes.NewReader(huge)); err == nil || !strings.Contains(err.Error(), "limit") {
+		t.Errorf("Expected an oversized text length to be rejected, got %v", err)
+	}
+}
+
+func TestDriverReplay(t *testing.T) {
+	th := newGoldenTheme()
+	type frame struct {
+		at   time.Duration
+		size image.Point
+	}
+	var frames []frame
+	d := th.NewDriver(image.Pt(10, 10), func(g C) D {
+		frames = append(frames, frame{g.Now.Sub(driverEpoch), g.Constraints.Max})
+		return D{}
+	})
+	frames = nil
+	d.Replay(testRecording)
+	want := []frame{{0, image.Pt(320, 240)}, {16 * time.Millisecond, image.Pt(320, 240)}, {400 * time.Millisecond, image.Pt(640, 480)}}
+	if !reflect.DeepEqual(frames, want) {
+		t.Errorf("Expected frames %v, got %v", want, frames)
+	}
+}
+
+func TestWindowReplay(t *testing.T) {
+	var times []time.Duration
+	var sizes []image.Point
+	var metrics []unit.Metric
+	start := time.Now()
+	done := false
+	p := (&Window{}).Replay(testRecording).OnDone(func() { done = true })
+	root := func(g C) D {
+		times = append(times, g.Now.Sub(start))
+		sizes = append(sizes, g.Constraints.Max)
+		metrics = append(metrics, g.Metric)
+		return D{}
+	}
+	for i := 0; i < 4; i++ {
+		gtx := layout.Context{Ops: new(op.Ops), Now: start.Add(time.Duration(i) * time.Second),
+			Constraints: layout.Exact(image.Pt(320, 240))}
+		p.Layout(gtx, root)
+	}
+	// Recorded times until the replay is done, then the live clock
+	want := []time.Duration{0, 16 * time.Millisecond, 400 * time.Millisecond, 3 * time.Second}
+	if !reflect.DeepEqual(times, want) || !done || !p.Done() {
+		t.Errorf("Expected frame times %v and the replay done, got %v and %v", want, times, done)
+	}
+	// Recorded sizes and densities, then the window's own
+	wantSizes := []image.Point{image.Pt(320, 240), image.Pt(320, 240), image.Pt(640, 480), image.Pt(320, 240)}
+	if !reflect.DeepEqual(sizes, wantSizes) {
+		t.Errorf("Expected frame sizes %v, got %v", wantSizes, sizes)
+	}
+	if metrics[2].PxPerDp != 2 || metrics[3].PxPerDp != 0 {
+		t.Errorf("Expected the recorded density while replaying, got %v", metrics)
+	}
+}
+
+func TestRecordEditorTyping(t *testing.T) {
+	th := newGoldenTheme()
+	editorLayout := func(e *widget.Editor) W {
+		return func(g C) D {
+			return e.Layout(g, th.Shaper, th.resolveFont(font.Font{}), unit.Sp(th.TextSize), op.CallOp{}, op.CallOp{})
+		}
+	}
+	var buf bytes.Buffer
+	typed := new(widget.Editor)
+	r := (&Window{}).Record(&buf)
+	d := th.NewDriver(image.Pt(200, 40), func(g C) D { return r.Layout(g, editorLayout(typed)) })
+	d.Frame()
+	d.Click(image.Pt(20, 10))
+	d.Queue(key.EditEvent{Text: "hi"})
+	d.Frame()
+	d.Queue(key.SelectionEvent{Start: 1, End: 1}, key.EditEvent{Range: key.Range{Start: 1, End: 1}, Text: "-"})
+	d.Frame()
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if typed.Text() != "h-i" {
+		t.Errorf("Expected typing to reach the editor through the recorder, got %q", typed.Text())
+	}
+
+	rec, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := new(widget.Editor)
+	p := (&Window{}).Replay(rec)
+	d = th.NewDriver(image.Pt(200, 40), func(g C) D { return p.Layout(g, editorLayout(replayed)) })
+	for !p.Done() {
+		d.Frame()
+	}
+	if replayed.Text() != typed.Text() {
+		t.Errorf("Expected the replay to type %q, got %q", typed.Text(), replayed.Text())
+	}
+}
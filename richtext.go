@@ -0,0 +1,610 @@
+package fromage
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gio.mleku.dev/f32"
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/gesture"
+	"gio.mleku.dev/io/clipboard"
+	"gio.mleku.dev/io/event"
+	"gio.mleku.dev/io/key"
+	"gio.mleku.dev/io/pointer"
+	"gio.mleku.dev/io/system"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+	"golang.org/x/image/math/fixed"
+)
+
+// Span is a run of text with a single style within a RichText
+type Span struct {
+	// Text content, which may contain newlines
+	Text string
+	// Bold uses a bold weight
+	Bold bool
+	// Italic uses an italic style
+	Italic bool
+	// Monospace uses the rich text's monospace typeface
+	Monospace bool
+	// Color is the text color (zero = theme text color, or theme primary for links)
+	Color color.NRGBA
+	// Background is a highlight drawn behind the text (zero = none)
+	Background color.NRGBA
+	// Scale is the text size relative to the rich text's base size (0 = 1)
+	Scale float32
+	// Link makes the span clickable, reporting this value to the link callback
+	Link string
+}
+
+// richRun is the part of a span shaped on one line
+type richRun struct {
+	span int
+	// Rune offsets of the run's text within the rich text
+	start, end int
+	glyphs     []text.Glyph
+	clusters   []richCluster
+	// x is the position of the run's origin within its line, before alignment
+	x     fixed.Int26_6
+	width fixed.Int26_6
+	// rect spans the line's height; baseline is the y of the glyphs' dot
+	rect     image.Rectangle
+	baseline int
+}
+
+// richCluster is a glyph cluster of a run: the runes it shows and its extent relative to
+// the run's origin
+type richCluster struct {
+	start, runes int
+	x0, x1       fixed.Int26_6
+	space        bool
+}
+
+// richShapeKey identifies the inputs of the last shaping, so it is only redone when the
+// width, text size, shaper or locale change
+type richShapeKey struct {
+	width   int
+	pxPerSp float32
+	size    unit.Dp
+	shaper  *text.Shaper
+	locale  system.Locale
+}
+
+// RichText is a wrapping text widget made of styled spans, with clickable links and a
+// selection that can be copied to the clipboard. Each span is shaped with the text
+// shaper's line breaking, so text wraps between words, within URLs and CJK text, and
+// inside words too long for a line. The shaped lines are kept until the width, text size
+// or spans change.
+type RichText struct {
+	// Theme reference
+	theme *Theme
+	// Styled runs of text
+	spans []Span
+	// Base type style for spans
+	style TypeStyle
+	// Horizontal alignment of each line
+	alignment text.Alignment
+	// Typeface used for monospace spans
+	monospace font.Typeface
+	// Called with the span's Link value when a link is clicked
+	onLink func(link string)
+	// Click gestures of link spans, by span index
+	links map[int]*gesture.Click
+	// Shaped runs, their total size, and the inputs they were shaped with
+	runs   []richRun
+	size   image.Point
+	shaped richShapeKey
+	dirty  bool
+	// Selection state, as rune offsets into the text
+	anchor, caret int
+	selecting     bool
+}
+
+// NewRichText creates an empty rich text widget
+func (t *Theme) NewRichText() *RichText {
+	return &RichText{
+		theme:     t,
+		style:     t.typography().BodyLarge,
+		monospace: "Go Mono, monospace",
+		links:     make(map[int]*gesture.Click),
+		dirty:     true,
+	}
+}
+
+// Spans replaces all spans and clears the selection
+func (r *RichText) Spans(spans ...Span) *RichText {
+	r.spans = nil
+	r.dirty = true
+	r.links = make(map[int]*gesture.Click)
+	r.ClearSelection()
+	for _, s := range spans {
+		r.Span(s)
+	}
+	return r
+}
+
+// Span appends a styled span
+func (r *RichText) Span(s Span) *RichText {
+	idx := len(r.spans)
+	r.spans = append(r.spans, s)
+	r.dirty = true
+	if s.Link != "" {
+		r.links[idx] = &gesture.Click{}
+	}
+	return r
+}
+
+// Plain appends unstyled text
+func (r *RichText) Plain(text string) *RichText { return r.Span(Span{Text: text}) }
+
+// Bold appends bold text
+func (r *RichText) Bold(text string) *RichText { return r.Span(Span{Text: text, Bold: true}) }
+
+// Italic appends italic text
+func (r *RichText) Italic(text string) *RichText { return r.Span(Span{Text: text, Italic: true}) }
+
+// Code appends monospace text
+func (r *RichText) Code(text string) *RichText { return r.Span(Span{Text: text, Monospace: true}) }
+
+// Link appends a clickable link showing text and reporting link when clicked
+func (r *RichText) Link(text, link string) *RichText {
+	return r.Span(Span{Text: text, Link: link})
+}
+
+// Style sets the base type style of the spans
+func (r *RichText) Style(style TypeStyle) *RichText {
+	r.style, r.dirty = style, true
+	return r
+}
+
+// Alignment sets the horizontal alignment of each line within the maximum width
+func (r *RichText) Alignment(alignment text.Alignment) *RichText {
+	r.alignment, r.dirty = alignment, true
+	return r
+}
+
+// MonospaceTypeface sets the typeface used for monospace spans
+func (r *RichText) MonospaceTypeface(typeface font.Typeface) *RichText {
+	r.monospace, r.dirty = typeface, true
+	return r
+}
+
+// OnLink sets the callback for link clicks
+func (r *RichText) OnLink(fn func(link string)) *RichText {
+	r.onLink = fn
+	return r
+}
+
+// Text returns the plain text of all spans
+func (r *RichText) Text() string {
+	var b strings.Builder
+	for _, s := range r.spans {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// SelectedText returns the selected text, or an empty string without a selection
+func (r *RichText) SelectedText() string {
+	from, to := r.selection()
+	if from == to {
+		return ""
+	}
+	return string([]rune(r.Text())[from:to])
+}
+
+// SelectAll selects all text
+func (r *RichText) SelectAll() *RichText {
+	r.anchor, r.caret = 0, utf8.RuneCountInString(r.Text())
+	return r
+}
+
+// ClearSelection removes the selection
+func (r *RichText) ClearSelection() *RichText {
+	r.anchor, r.caret = 0, 0
+	return r
+}
+
+// CopySelection writes the selected text to the clipboard
+func (r *RichText) CopySelection(gtx C) {
+	if text := r.SelectedText(); text != "" {
+		gtx.Execute(clipboard.WriteCmd{Type: "application/text", Data: io.NopCloser(strings.NewReader(text))})
+	}
+}
+
+// selection returns the selected rune range in order
+func (r *RichText) selection() (from, to int) {
+	if r.anchor <= r.caret {
+		return r.anchor, r.caret
+	}
+	return r.caret, r.anchor
+}
+
+// Layout renders the rich text, wrapping at the maximum width
+func (r *RichText) Layout(gtx C) D {
+	r.update(gtx)
+	r.shape(gtx)
+	size := gtx.Constraints.Constrain(r.size)
+
+	// The whole area handles selection drags and copy shortcuts. It goes first so the link
+	// areas added after it are on top.
+	area := clip.Rect(image.Rectangle{Max: size}).Push(gtx.Ops)
+	pointer.CursorText.Add(gtx.Ops)
+	event.Op(gtx.Ops, r)
+	area.Pop()
+
+	// Paint backgrounds, selection, text and link areas
+	selection := r.theme.Colors.Primary()
+	selection.A = 0x50
+	from, to := r.selection()
+	for i := range r.runs {
+		run := &r.runs[i]
+		span := r.spans[run.span]
+		if span.Background.A > 0 {
+			paint.FillShape(gtx.Ops, span.Background, clip.Rect(run.rect).Op())
+		}
+		if from < run.end && to > run.start {
+			sel := run.rect
+			sel.Min.X, sel.Max.X = run.rect.Min.X+run.offsetX(max(from, run.start)), run.rect.Min.X+run.offsetX(min(to, run.end))
+			paint.FillShape(gtx.Ops, selection, clip.Rect(sel).Op())
+		}
+		r.paintRun(gtx, run, r.spanColor(span))
+		if click, ok := r.links[run.span]; ok {
+			// Underline links
+			underline := image.Rect(run.rect.Min.X, run.rect.Max.Y-gtx.Dp(1), run.rect.Max.X, run.rect.Max.Y)
+			paint.FillShape(gtx.Ops, r.spanColor(span), clip.Rect(underline).Op())
+			// Pass the pointer through so selection drags can start on a link
+			pass := pointer.PassOp{}.Push(gtx.Ops)
+			area := clip.Rect(run.rect).Push(gtx.Ops)
+			pointer.CursorPointer.Add(gtx.Ops)
+			click.Add(gtx.Ops)
+			area.Pop()
+			pass.Pop()
+		}
+	}
+	return D{Size: size}
+}
+
+// paintRun draws the glyphs of a run in a color
+func (r *RichText) paintRun(gtx C, run *richRun, c color.NRGBA) {
+	if len(run.glyphs) == 0 {
+		return
+	}
+	first := run.glyphs[0]
+	origin := f32.Pt(float32(run.rect.Min.X)+fixedToFloat(first.X), float32(run.baseline))
+	t := op.Affine(f32.AffineId().Offset(origin)).Push(gtx.Ops)
+	outline := clip.Outline{Path: r.theme.Shaper.Shape(run.glyphs)}.Op().Push(gtx.Ops)
+	paint.ColorOp{Color: c}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	outline.Pop()
+	if call := r.theme.Shaper.Bitmaps(run.glyphs); call != (op.CallOp{}) {
+		call.Add(gtx.Ops)
+	}
+	t.Pop()
+}
+
+// offsetX returns the x position of a rune offset within the run, relative to its rect
+func (run *richRun) offsetX(offset int) int {
+	for _, c := range run.clusters {
+		if offset <= c.start {
+			return c.x0.Round()
+		}
+	}
+	return run.width.Round()
+}
+
+// update handles link clicks, selection drags and copy shortcuts from the last frame
+func (r *RichText) update(gtx C) {
+	for idx, click := range r.links {
+		for {
+			e, ok := click.Update(gtx.Source)
+			if !ok {
+				break
+			}
+			if e.Kind == gesture.KindClick && r.onLink != nil {
+				r.onLink(r.spans[idx].Link)
+			}
+		}
+	}
+	for {
+		ev, ok := gtx.Event(
+			pointer.Filter{Target: r, Kinds: pointer.Press | pointer.Drag | pointer.Release | pointer.Cancel},
+			key.FocusFilter{Target: r},
+			key.Filter{Focus: r, Name: "C", Required: key.ModShortcut},
+			key.Filter{Focus: r, Name: "A", Required: key.ModShortcut},
+		)
+		if !ok {
+			break
+		}
+		switch e := ev.(type) {
+		case pointer.Event:
+			r.pointer(gtx, e)
+		case key.Event:
+			if e.State != key.Press {
+				continue
+			}
+			switch e.Name {
+			case "C":
+				r.CopySelection(gtx)
+			case "A":
+				r.SelectAll()
+			}
+		}
+	}
+}
+
+// pointer updates the selection from a pointer event
+func (r *RichText) pointer(gtx C, e pointer.Event) {
+	pos := image.Pt(int(e.Position.X), int(e.Position.Y))
+	switch e.Kind {
+	case pointer.Press:
+		if e.Buttons != pointer.ButtonPrimary {
+			return
+		}
+		gtx.Execute(key.FocusCmd{Tag: r})
+		r.anchor = r.offsetAt(pos)
+		r.caret = r.anchor
+		r.selecting = true
+	case pointer.Drag:
+		if r.selecting {
+			r.caret = r.offsetAt(pos)
+		}
+	case pointer.Release, pointer.Cancel:
+		r.selecting = false
+	}
+}
+
+// offsetAt returns the rune offset nearest to a point, from the last frame's layout
+func (r *RichText) offsetAt(pos image.Point) int {
+	var best *richRun
+	bestDist := -1
+	for i := range r.runs {
+		run := &r.runs[i]
+		// Rows outweigh columns so the nearest run on the pointer's line wins
+		dx := max(run.rect.Min.X-pos.X, 0, pos.X-run.rect.Max.X)
+		dy := max(run.rect.Min.Y-pos.Y, 0, pos.Y-run.rect.Max.Y)
+		if d := dy*1000 + dx; bestDist < 0 || d < bestDist {
+			best, bestDist = run, d
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	x := fixed.I(pos.X - best.rect.Min.X)
+	for _, c := range best.clusters {
+		if x < (c.x0+c.x1)/2 {
+			return c.start
+		}
+	}
+	return best.end
+}
+
+// spanFont returns the font of a span
+func (r *RichText) spanFont(span Span) font.Font {
+	f := r.style.Font
+	if span.Bold {
+		f.Weight = font.Bold
+	}
+	if span.Italic {
+		f.Style = font.Italic
+	}
+	if span.Monospace {
+		f.Typeface = r.monospace
+	}
+	return r.theme.resolveFont(f)
+}
+
+// spanSize returns the text size of a span
+func (r *RichText) spanSize(span Span) unit.Sp {
+	scale := span.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	return unit.Sp(float32(r.style.Size(r.theme.TextSize)) * scale)
+}
+
+// spanColor returns the text color of a span
+func (r *RichText) spanColor(span Span) color.NRGBA {
+	switch {
+	case span.Color.A > 0:
+		return span.Color
+	case span.Link != "":
+		return r.theme.Colors.Primary()
+	default:
+		return r.theme.Colors.OnBackground()
+	}
+}
+
+// shape breaks the spans into lines at the maximum width, unless the last shaping still
+// applies. A span continues on the line the previous one ended on; each line of a span is
+// shaped as one run, keeping its kerning and ligatures.
+func (r *RichText) shape(gtx C) {
+	key := richShapeKey{width: gtx.Constraints.Max.X, pxPerSp: gtx.Metric.PxPerSp,
+		size: r.theme.TextSize, shaper: r.theme.Shaper, locale: gtx.Locale}
+	if !r.dirty && key == r.shaped {
+		return
+	}
+	r.dirty, r.shaped = false, key
+	r.runs, r.size = r.runs[:0], image.Point{}
+	maxWidth := gtx.Constraints.Max.X
+	var (
+		x         fixed.Int26_6
+		y         int
+		lineStart int
+		offset    int
+		// Metrics of the line's font, for lines without text
+		empty text.Glyph
+	)
+	finishLine := func() {
+		ascent, descent := empty.Ascent, empty.Descent
+		if lineStart < len(r.runs) {
+			ascent, descent = 0, 0
+		}
+		for _, run := range r.runs[lineStart:] {
+			for _, g := range run.glyphs {
+				ascent, descent = max(ascent, g.Ascent), max(descent, g.Descent)
+			}
+		}
+		top, height := 0, ascent.Ceil()+descent.Ceil()
+		if r.style.LineHeight > 0 {
+			lineHeight := int(r.style.LineHeight*float32(gtx.Sp(r.style.Size(r.theme.TextSize))) + .5)
+			if lineHeight > height {
+				top, height = (lineHeight-height)/2, lineHeight
+			}
+		}
+		// Trailing whitespace does not count towards the alignment
+		width := x
+		if lineStart < len(r.runs) {
+			last := &r.runs[len(r.runs)-1]
+			for i := len(last.clusters) - 1; i >= 0 && last.clusters[i].space; i-- {
+				width = last.x + last.clusters[i].x0
+			}
+		}
+		shift := 0
+		switch r.alignment {
+		case text.Middle:
+			shift = max(maxWidth-width.Ceil(), 0) / 2
+		case text.End:
+			shift = max(maxWidth-width.Ceil(), 0)
+		}
+		for i := lineStart; i < len(r.runs); i++ {
+			run := &r.runs[i]
+			run.rect = image.Rect(run.x.Round()+shift, y, (run.x+run.width).Round()+shift, y+height)
+			run.baseline = y + top + ascent.Ceil()
+			r.size.X = max(r.size.X, run.rect.Max.X)
+		}
+		if r.alignment != text.Start {
+			r.size.X = maxWidth
+		}
+		y += height
+		r.size.Y = y
+		x, lineStart = 0, len(r.runs)
+	}
+	for i, span := range r.spans {
+		params := text.Parameters{
+			Font:             r.spanFont(span),
+			PxPerEm:          fixed.I(gtx.Sp(r.spanSize(span))),
+			MaxLines:         1,
+			Truncator:        "\u200b",
+			Locale:           gtx.Locale,
+			DisableSpaceTrim: true,
+		}
+		if glyphs, _ := r.shapeLine(params, ""); len(glyphs) > 0 {
+			empty = glyphs[0]
+		}
+		for j, line := range strings.Split(span.Text, "\n") {
+			if j > 0 {
+				finishLine()
+				offset++
+			}
+			for line != "" {
+				// Words move to the next line rather than break, unless they do not fit on
+				// a line of their own
+				params.MaxWidth, params.WrapPolicy = maxWidth-x.Ceil(), text.WrapWords
+				if x == 0 {
+					params.WrapPolicy = text.WrapHeuristically
+				}
+				glyphs, n := r.shapeLine(params, line)
+				if n == 0 && x > 0 {
+					finishLine()
+					continue
+				}
+				if n == 0 {
+					// Not even a character fits; show one per line
+					glyphs, n = r.shapeFirstCluster(params, line)
+				}
+				run := richRun{span: i, start: offset, end: offset + n, glyphs: glyphs, x: x}
+				run.clusters, run.width = richClusters(glyphs, offset, line)
+				r.runs = append(r.runs, run)
+				x += run.width
+				offset += n
+				line = line[byteOffset(line, n):]
+				if line != "" {
+					finishLine()
+				}
+			}
+		}
+	}
+	if len(r.spans) > 0 {
+		finishLine()
+	}
+}
+
+// shapeLine shapes as much of s as fits on one line, returning its glyphs without the
+// truncator and the number of runes they show
+func (r *RichText) shapeLine(params text.Parameters, s string) (glyphs []text.Glyph, runes int) {
+	r.theme.Shaper.LayoutString(params, s)
+	for {
+		g, ok := r.theme.Shaper.NextGlyph()
+		if !ok {
+			return
+		}
+		if g.Flags&text.FlagTruncator != 0 {
+			continue
+		}
+		glyphs = append(glyphs, g)
+		if g.Flags&text.FlagClusterBreak != 0 {
+			runes += int(g.Runes)
+		}
+	}
+}
+
+// shapeFirstCluster shapes the first glyph cluster of s, for lines too narrow to show it
+func (r *RichText) shapeFirstCluster(params text.Parameters, s string) (glyphs []text.Glyph, runes int) {
+	params.MaxWidth = 1 << 24
+	all, _ := r.shapeLine(params, s)
+	for i, g := range all {
+		if g.Flags&text.FlagClusterBreak != 0 {
+			return all[: i+1 : i+1], int(g.Runes)
+		}
+	}
+	return all, utf8.RuneCountInString(s)
+}
+
+// richClusters groups a run's glyphs into clusters, numbering their runes from offset,
+// and returns them with the run's width
+func richClusters(glyphs []text.Glyph, offset int, s string) (clusters []richCluster, width fixed.Int26_6) {
+	runes := []rune(s)
+	c := richCluster{start: offset, x0: -1}
+	for _, g := range glyphs {
+		if c.x0 < 0 || g.X < c.x0 {
+			c.x0 = g.X
+		}
+		c.x1 = max(c.x1, g.X+g.Advance)
+		if g.Flags&text.FlagClusterBreak == 0 {
+			continue
+		}
+		c.runes = int(g.Runes)
+		c.space = c.runes > 0
+		for _, ru := range runes[c.start-offset : min(c.start-offset+c.runes, len(runes))] {
+			c.space = c.space && unicode.IsSpace(ru)
+		}
+		clusters = append(clusters, c)
+		width = max(width, c.x1)
+		c = richCluster{start: c.start + c.runes, x0: -1}
+	}
+	return clusters, width
+}
+
+// byteOffset returns the byte offset of the rune at index n of s
+func byteOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// fixedToFloat converts a 26.6 fixed point number to a float
+func fixedToFloat(i fixed.Int26_6) float32 {
+	return float32(i) / 64
+}
@@ -0,0 +1,226 @@
+package fromage
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+)
+
+// layoutRichText lays out a rich text at most width pixels wide
+func layoutRichText(rt *RichText, width int) D {
+	return rt.Layout(layout.Context{
+		Ops:         new(op.Ops),
+		Metric:      unit.Metric{PxPerDp: 1, PxPerSp: 1},
+		Constraints: layout.Constraints{Max: image.Pt(width, 1000)},
+	})
+}
+
+// richLines returns the text of each laid out line
+func richLines(rt *RichText) []string {
+	runes := []rune(rt.Text())
+	var lines []string
+	top := -1
+	for _, run := range rt.runs {
+		if run.rect.Min.Y != top {
+			lines = append(lines, "")
+			top = run.rect.Min.Y
+		}
+		lines[len(lines)-1] += string(runes[run.start:run.end])
+	}
+	return lines
+}
+
+func TestRichTextWraps(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("aaa bbb ccc\nddd")
+	layoutRichText(rt, 1000)
+	if len(rt.runs) != 2 {
+		t.Fatalf("Expected a run for each line of the span, got %d", len(rt.runs))
+	}
+	// Wide enough for two words
+	width := rt.runs[0].offsetX(7) + 1
+	layoutRichText(rt, width)
+	want := []string{"aaa bbb ", "ccc", "ddd"}
+	if got := richLines(rt); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected lines %q, got %q", want, got)
+	}
+}
+
+func TestRichTextBreaksLongText(t *testing.T) {
+	th := newGoldenTheme()
+	for _, s := range []string{"https://example.com/a/very/long/path", "日本語のテキストを折り返す", "Supercalifragilistic"} {
+		rt := th.NewRichText().Plain("See " + s)
+		dims := layoutRichText(rt, 80)
+		lines := richLines(rt)
+		if len(lines) < 3 || strings.Join(lines, "") != rt.Text() {
+			t.Errorf("Expected %q to wrap over several lines, got %q", s, lines)
+		}
+		for _, run := range rt.runs {
+			if run.rect.Max.X > 80 {
+				t.Errorf("Expected %q to fit in 80 pixels, got a run to %d", s, run.rect.Max.X)
+			}
+		}
+		if dims.Size.X > 80 {
+			t.Errorf("Expected %q to be at most 80 pixels wide, got %d", s, dims.Size.X)
+		}
+	}
+
+	// A line too narrow for any character shows one per line
+	rt := th.NewRichText().Plain("abc")
+	layoutRichText(rt, 1)
+	if got := richLines(rt); len(got) != 3 {
+		t.Errorf("Expected a character per line, got %q", got)
+	}
+}
+
+func TestRichTextEmptyLines(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("\nabc")
+	dims := layoutRichText(rt, 200)
+	if len(rt.runs) != 1 {
+		t.Fatalf("Expected one run, got %d", len(rt.runs))
+	}
+	line := rt.runs[0].rect
+	if line.Min.Y == 0 || line.Min.Y != line.Dy() || dims.Size.Y != 2*line.Dy() {
+		t.Errorf("Expected the leading empty line to be as tall as the text, got %v in %v", line, dims.Size)
+	}
+}
+
+func TestRichTextBaseline(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("big ").Span(Span{Text: "small", Scale: 0.5})
+	layoutRichText(rt, 400)
+	if len(rt.runs) != 2 {
+		t.Fatalf("Expected two runs, got %d", len(rt.runs))
+	}
+	big, small := rt.runs[0], rt.runs[1]
+	if big.baseline != small.baseline || big.rect.Min.Y != small.rect.Min.Y {
+		t.Errorf("Expected both spans on one baseline, got %d and %d", big.baseline, small.baseline)
+	}
+	if small.rect.Min.X != big.rect.Max.X {
+		t.Errorf("Expected the small span to follow the big one, got %v after %v", small.rect, big.rect)
+	}
+}
+
+func TestRichTextAlignment(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("ab").Alignment(text.End)
+	layoutRichText(rt, 100)
+	if r := rt.runs[0].rect; r.Max.X != 100 {
+		t.Errorf("Expected end alignment at 100, got %v", r)
+	}
+	rt.Alignment(text.Middle)
+	layoutRichText(rt, 100)
+	if r := rt.runs[0].rect; max(r.Min.X-(100-r.Max.X), (100-r.Max.X)-r.Min.X) > 1 {
+		t.Errorf("Expected the text centered, got %v", r)
+	}
+}
+
+func TestRichTextShapingCache(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("cached text")
+	layoutRichText(rt, 200)
+	glyphs := &rt.runs[0].glyphs[0]
+	layoutRichText(rt, 200)
+	if &rt.runs[0].glyphs[0] != glyphs {
+		t.Error("Expected the shaped glyphs to be kept across frames")
+	}
+	layoutRichText(rt, 300)
+	if &rt.runs[0].glyphs[0] == glyphs {
+		t.Error("Expected a new width to shape again")
+	}
+	glyphs = &rt.runs[0].glyphs[0]
+	rt.Bold(" more")
+	layoutRichText(rt, 300)
+	if &rt.runs[0].glyphs[0] == glyphs || len(rt.runs) != 2 {
+		t.Error("Expected new spans to shape again")
+	}
+}
+
+func TestRichTextSelection(t *testing.T) {
+	th := &Theme{Colors: NewColors()}
+	rt := th.NewRichText().Plain("one two ").Bold("three").Link(" four", "https://example.com")
+	if rt.Text() != "one two three four" {
+		t.Errorf("Unexpected text %q", rt.Text())
+	}
+	if rt.SelectedText() != "" {
+		t.Error("Expected no selection")
+	}
+	if got := rt.SelectAll().SelectedText(); got != rt.Text() {
+		t.Errorf("Expected select all to return all text, got %q", got)
+	}
+	rt.anchor, rt.caret = 13, 4
+	if got := rt.SelectedText(); got != "two three" {
+		t.Errorf("Expected reversed selection %q, got %q", "two three", got)
+	}
+	if rt.ClearSelection().SelectedText() != "" {
+		t.Error("Expected the selection to be cleared")
+	}
+}
+
+func TestRichTextSelectDrag(t *testing.T) {
+	th := newGoldenTheme()
+	rt := th.NewRichText().Plain("one two three")
+	d := th.NewDriver(image.Pt(300, 40), rt.Layout)
+	run := rt.runs[0]
+	y := run.rect.Min.Y + run.rect.Dy()/2
+	d.Drag(image.Pt(run.rect.Min.X+run.offsetX(4)+1, y), image.Pt(run.rect.Min.X+run.offsetX(7)-1, y))
+	if got := rt.SelectedText(); got != "two" {
+		t.Errorf("Expected dragging over the second word to select it, got %q", got)
+	}
+}
+
+func TestRichTextSpanColor(t *testing.T) {
+	th := &Theme{Colors: NewColors()}
+	rt := th.NewRichText()
+	if c := rt.spanColor(Span{Link: "x"}); c != th.Colors.Primary() {
+		t.Errorf("Expected links in the primary color, got %v", c)
+	}
+	red := color.NRGBA{R: 255, A: 255}
+	if c := rt.spanColor(Span{Link: "x", Color: red}); c != red {
+		t.Errorf("Expected an explicit color to win, got %v", c)
+	}
+	if c := rt.spanColor(Span{}); c != th.Colors.OnBackground() {
+		t.Errorf("Expected plain text in the text color, got %v", c)
+	}
+	rt.Link("a", "b")
+	if _, ok := rt.links[0]; !ok {
+		t.Error("Expected a click gesture for the link span")
+	}
+}
+
+func TestRichTextLinkClick(t *testing.T) {
+	th := newGoldenTheme()
+	var clicked []string
+	rt := th.NewRichText().
+		Plain("Read the ").
+		Link("docs", "https://example.com/docs").
+		OnLink(func(link string) { clicked = append(clicked, link) })
+	d := th.NewDriver(image.Pt(300, 60), rt.Layout)
+
+	var link image.Rectangle
+	for _, run := range rt.runs {
+		if run.span == 1 {
+			link = run.rect
+		}
+	}
+	if link.Empty() {
+		t.Fatal("Expected the link to be laid out")
+	}
+	d.Click(link.Min.Add(link.Size().Div(2)))
+	if len(clicked) != 1 || clicked[0] != "https://example.com/docs" {
+		t.Errorf("Expected one click on the link, got %v", clicked)
+	}
+
+	// Clicking the plain text reports nothing
+	d.Click(image.Pt(2, link.Min.Y+link.Dy()/2))
+	if len(clicked) != 1 {
+		t.Errorf("Expected no link click outside the link, got %v", clicked)
+	}
+}
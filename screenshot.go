@@ -0,0 +1,72 @@
+package fromage
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/mleku/fromage/internal/offscreen"
+	"github.com/mleku/fromage/internal/screenshot"
+	"lol.mleku.dev/chk"
+)
+
+// AllowScreenshots lets RunFrames render the window's content to a PNG file instead of
+// opening the window when the FROMAGE_SCREENSHOT environment variables request one, as
+// the screenshots tool under cmd does for the demos
+func (w *Window) AllowScreenshots() *Window {
+	w.screenshots = true
+	return w
+}
+
+// Screenshot renders the content RunFrames would lay out with frame to a PNG file, at
+// size in dp and scale pixels per dp, without opening the window. It lays out frames
+// with a Driver, whose first frame lets widgets that measure themselves render settled
+// in the second.
+func (w *Window) Screenshot(path string, size image.Point, scale float32, frame func(gtx C)) error {
+	px := image.Pt(int(float32(size.X)*scale+.5), int(float32(size.Y)*scale+.5))
+	d := w.Theme.NewDriver(px, func(gtx C) D {
+		w.Theme.Pool.Reset()
+		frame(gtx)
+		return D{Size: px}
+	}).Scale(scale)
+	img, err := offscreen.Render(px, d.Ops())
+	if err != nil {
+		return fmt.Errorf("screenshot: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("screenshot: %w", err)
+	}
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return fmt.Errorf("screenshot: encoding %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// screenshotFromEnv renders the screenshot the environment requests, if screenshots are
+// allowed, and reports whether one was requested
+func (w *Window) screenshotFromEnv(frame func(gtx C)) bool {
+	if !w.screenshots {
+		return false
+	}
+	spec, ok, err := screenshot.FromEnv()
+	if !ok {
+		return false
+	}
+	if err != nil {
+		chk.E(fmt.Errorf("screenshot: %w", err))
+		return true
+	}
+	switch spec.Mode {
+	case "light":
+		w.Theme.Colors.SetTransition(0)
+		w.Theme.Colors.SetThemeMode(ThemeModeLight)
+	case "dark":
+		w.Theme.Colors.SetTransition(0)
+		w.Theme.Colors.SetThemeMode(ThemeModeDark)
+	}
+	chk.E(w.Screenshot(spec.Path, spec.Size, spec.Scale, frame))
+	return true
+}
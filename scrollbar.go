@@ -14,6 +14,8 @@ import (
 
 // Scrollbar is a scrollbar widget for indicating scroll position and allowing scrolling
 type Scrollbar struct {
+	// Theme reference
+	theme *Theme
 	// Viewport represents the visible portion of the content (0-1)
 	viewport float32
 	// Position represents the scroll position within the content (0-1)
@@ -57,6 +59,7 @@ const (
 // NewScrollbar creates a new scrollbar
 func (t *Theme) NewScrollbar(orientation Orientation) *Scrollbar {
 	sb := &Scrollbar{
+		theme:       t,
 		changeHook:  func(float32) {},
 		orientation: orientation,
 		width:       t.TextSize, // Default to 1 text height wide
@@ -146,7 +149,7 @@ func (s *Scrollbar) handlePress(e pointer.Event) {
 	} else {
 		// Click is on track - start tracking for long press
 		s.trackPressed = true
-		s.trackPressStart = time.Now()
+		s.trackPressStart = s.gtx.Now
 		if clickPos < thumbStart {
 			s.trackPressSide = -1 // Left/up side
 		} else {
@@ -393,7 +396,7 @@ func (s *Scrollbar) handleTrackClick(clickPos, thumbStart, thumbEnd float32, tra
 // startAnimation starts a smooth animation to the target position
 func (s *Scrollbar) startAnimation(targetPos float32, gtx layout.Context) {
 	s.animating = true
-	s.animStartTime = time.Now()
+	s.animStartTime = gtx.Now
 	s.animStartPos = s.position
 	s.animTargetPos = targetPos
 	// Request immediate frame update for animation
@@ -406,30 +409,26 @@ func (s *Scrollbar) updateAnimation(gtx layout.Context) {
 		return
 	}
 
-	now := gtx.Now
-	elapsed := float32(now.Sub(s.animStartTime).Seconds())
+	motion := s.theme.motion()
 
 	// Determine animation duration based on target position
-	var duration float32
+	var duration time.Duration
 	if s.animTargetPos == 0 || s.animTargetPos == 1 {
-		// Fast animation for end positions (100ms)
-		duration = float32(0.1)
+		// Fast animation for end positions
+		duration = motion.Short
 	} else {
-		// Normal animation for track clicks (250ms)
-		duration = float32(0.25)
+		// Normal animation for track clicks
+		duration = motion.Medium
 	}
 
-	if elapsed >= duration {
+	easedProgress, done := motion.Progress(s.animStartTime, gtx.Now, duration, motion.Emphasized)
+	if done {
 		// Animation complete
 		s.position = s.animTargetPos
 		s.animating = false
 		s.changed = true
 		s.changeHook(s.position)
 	} else {
-		// Calculate progress with easing (ease-out cubic)
-		progress := elapsed / duration
-		easedProgress := 1 - (1-progress)*(1-progress)*(1-progress)
-
 		// Interpolate position
 		s.position = s.animStartPos + (s.animTargetPos-s.animStartPos)*easedProgress
 		s.changed = true
@@ -0,0 +1,107 @@
+package fromage
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"gio.mleku.dev/f32"
+	"gio.mleku.dev/op"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/unit"
+)
+
+// Spinner is an indeterminate circular progress indicator
+type Spinner struct {
+	// Theme reference
+	theme *Theme
+	// Arc color
+	color color.NRGBA
+	// Diameter of the spinner
+	size unit.Dp
+	// Stroke width of the arc
+	width unit.Dp
+	// Time of the first frame, from which the animation runs
+	start time.Time
+}
+
+// spinnerPeriod is the time of one rotation; the arc grows and shrinks over spinnerBreath
+const (
+	spinnerPeriod = 1200 * time.Millisecond
+	spinnerBreath = 1600 * time.Millisecond
+)
+
+// NewSpinner creates a spinner in the primary color, two text heights across
+func (t *Theme) NewSpinner() *Spinner {
+	return &Spinner{
+		theme: t,
+		color: t.Colors.Primary(),
+		size:  t.TextSize * 2,
+		width: t.TextSize / 6,
+	}
+}
+
+// Color sets the arc color
+func (s *Spinner) Color(color color.NRGBA) *Spinner {
+	s.color = color
+	return s
+}
+
+// Size sets the diameter
+func (s *Spinner) Size(size unit.Dp) *Spinner {
+	s.size = size
+	return s
+}
+
+// Width sets the stroke width
+func (s *Spinner) Width(width unit.Dp) *Spinner {
+	s.width = width
+	return s
+}
+
+// Layout draws the spinner and requests the next frame while animating. With reduced
+// motion the arc is drawn still.
+func (s *Spinner) Layout(gtx C) D {
+	if s.start.IsZero() {
+		s.start = gtx.Now
+	}
+	diameter := gtx.Dp(s.size)
+	width := float32(gtx.Dp(s.width))
+	radius := (float32(diameter) - width) / 2
+	center := f32.Pt(float32(diameter)/2, float32(diameter)/2)
+
+	rotation, sweep := 0.0, 0.75
+	if !s.theme.ReducedMotion() {
+		elapsed := gtx.Now.Sub(s.start).Seconds()
+		rotation = 2 * math.Pi * elapsed / spinnerPeriod.Seconds()
+		sweep = 0.1 + 0.65*(0.5-0.5*math.Cos(2*math.Pi*elapsed/spinnerBreath.Seconds()))
+		gtx.Execute(op.InvalidateCmd{})
+	}
+
+	// Faint track under the arc
+	track := s.color
+	track.A /= 5
+	paint.FillShape(gtx.Ops, track, clip.Stroke{Path: arcPath(gtx.Ops, center, radius, 0, 2*math.Pi), Width: width}.Op())
+	paint.FillShape(gtx.Ops, s.color, clip.Stroke{Path: arcPath(gtx.Ops, center, radius, rotation, sweep*2*math.Pi), Width: width}.Op())
+	return D{Size: image.Pt(diameter, diameter)}
+}
+
+// arcPath builds an open circular arc from the angle start, in radians clockwise from the
+// top, sweeping through the angle sweep
+func arcPath(ops *op.Ops, center f32.Point, radius float32, start, sweep float64) clip.PathSpec {
+	var p clip.Path
+	p.Begin(ops)
+	segments := max(int(sweep/(2*math.Pi)*48), 2)
+	for i := 0; i <= segments; i++ {
+		a := start + sweep*float64(i)/float64(segments)
+		pt := center.Add(f32.Pt(float32(math.Sin(a))*radius, -float32(math.Cos(a))*radius))
+		if i == 0 {
+			p.MoveTo(pt)
+		} else {
+			p.LineTo(pt)
+		}
+	}
+	return p.End()
+}
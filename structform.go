@@ -0,0 +1,577 @@
+package fromage
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op/clip"
+	"gio.mleku.dev/op/paint"
+	"gio.mleku.dev/text"
+)
+
+// StructForm lays out a form for the exported fields of a struct and keeps the two in
+// step: edits in the widgets are written to the struct, and changes the program makes to
+// the struct show in the widgets on the next frame. Each field is configured with a form
+// tag of comma-separated options:
+//
+//	label=Text     the field's label, by default its name split into words
+//	widget=Kind    checkbox (default) or switch for bools, slider (default) or number
+//	               for integers, slider for floats, radio for options, color for colors
+//	min=N, max=N   the range of a slider or number field, also checked by Validate
+//	step=N         the increment of a number field, by default 1
+//	options=A|B    makes a string or integer field an enum chosen with radio buttons;
+//	               an integer field holds the index of its option
+//	required       makes Validate reject the field's zero value
+//
+// A tag of "-" skips the field. Bools, integers, floats, enums and color.NRGBA are
+// supported; any other exported field must be skipped.
+type StructForm struct {
+	// Window reference, for the radio buttons
+	window *Window
+	// The struct the form edits
+	target reflect.Value
+	// One entry per field shown
+	fields []*formField
+	// Callback with the name of each field the user edits
+	onChange func(field string)
+}
+
+// formField is the widget of a struct field and the state that keeps them in step
+type formField struct {
+	// Name of the struct field
+	name string
+	// Options from the field's tag
+	tag formTag
+	// The field's widget
+	widget any
+	// sync brings the field and its widget in step and reports whether the widget
+	// edited the field
+	sync func() bool
+	// layout draws the field's row
+	layout W
+}
+
+// formTag holds the options of a field's form tag
+type formTag struct {
+	label    string
+	widget   string
+	min, max float64
+	hasMin   bool
+	hasMax   bool
+	step     float64
+	options  []string
+	required bool
+	skip     bool
+}
+
+// FieldError reports a problem with a struct field, naming the field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string { return fmt.Sprintf("%s: %v", e.Field, e.Err) }
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+var colorType = reflect.TypeOf(color.NRGBA{})
+
+// NewStructForm creates a form editing the struct ptr points to. It fails on invalid tags
+// and on exported fields of unsupported types.
+func (w *Window) NewStructForm(ptr any) (*StructForm, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("form: expected a pointer to a struct, got %T", ptr)
+	}
+	f := &StructForm{window: w, target: v.Elem(), onChange: func(string) {}}
+	st := f.target.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, err := parseFormTag(sf.Tag.Get("form"))
+		if err != nil {
+			return nil, &FieldError{Field: sf.Name, Err: err}
+		}
+		if tag.skip {
+			continue
+		}
+		if tag.label == "" {
+			tag.label = fieldLabel(sf.Name)
+		}
+		field := &formField{name: sf.Name, tag: tag}
+		if err = f.bindField(field, f.target.Field(i)); err != nil {
+			return nil, &FieldError{Field: sf.Name, Err: err}
+		}
+		f.fields = append(f.fields, field)
+	}
+	return f, nil
+}
+
+// OnChange sets the callback run with the name of each field the user edits
+func (f *StructForm) OnChange(fn func(field string)) *StructForm {
+	f.onChange = fn
+	return f
+}
+
+// Widget returns the widget of the named field, for styling: a *Checkbox, *Bool, *Int,
+// *Float, *RadioButtonGroup or *ColorSelector, or nil for a number field or a field not
+// in the form
+func (f *StructForm) Widget(field string) any {
+	for _, ff := range f.fields {
+		if ff.name == field {
+			if _, ok := ff.widget.(*numberField); ok {
+				return nil
+			}
+			return ff.widget
+		}
+	}
+	return nil
+}
+
+// Validate checks the struct against the required, min and max options of its fields and
+// returns a *FieldError for the first field that fails
+func (f *StructForm) Validate() error {
+	for _, field := range f.fields {
+		if err := field.validate(f.target.FieldByName(field.name)); err != nil {
+			return &FieldError{Field: field.name, Err: err}
+		}
+	}
+	return nil
+}
+
+// Sync writes pending edits to the struct and shows changes to the struct in the widgets.
+// Layout does this itself; Sync is for reading the struct between frames.
+func (f *StructForm) Sync() {
+	for _, field := range f.fields {
+		if field.sync() {
+			f.onChange(field.name)
+		}
+	}
+}
+
+// Layout draws the fields in a column, one row each
+func (f *StructForm) Layout(gtx C) D {
+	f.Sync()
+	th := f.window.Theme
+	column := th.VFlex()
+	for _, field := range f.fields {
+		column = column.Rigid(func(gtx C) D {
+			return layout.Inset{Bottom: th.TextSize / 2}.Layout(gtx, field.layout)
+		})
+	}
+	d := column.Layout(gtx)
+	f.Sync()
+	return d
+}
+
+// bindField creates the widget for a field of the struct
+func (f *StructForm) bindField(field *formField, v reflect.Value) error {
+	switch {
+	case len(field.tag.options) > 0:
+		if !isIntKind(v.Kind()) && v.Kind() != reflect.String {
+			return fmt.Errorf("options need a string or integer field, not %s", v.Type())
+		}
+		return f.bindEnum(field, v)
+	case v.Type() == colorType:
+		return f.bindColor(field, v)
+	case v.Kind() == reflect.Bool:
+		return f.bindBool(field, v)
+	case isIntKind(v.Kind()):
+		return f.bindInt(field, v)
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return f.bindFloat(field, v)
+	}
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// bindBool shows a bool as a checkbox or a switch
+func (f *StructForm) bindBool(field *formField, v reflect.Value) error {
+	th := f.window.Theme
+	s := newFieldSync(v, reflect.Value.Bool, reflect.Value.SetBool)
+	field.sync = s.sync
+	switch field.tag.widget {
+	case "", "checkbox":
+		checkbox := th.NewCheckbox(v.Bool()).Label(field.tag.label).Bind(s.value)
+		field.widget, field.layout = checkbox, checkbox.Layout
+	case "switch":
+		sw := th.Switch(v.Bool()).Bind(s.value)
+		field.widget = sw
+		field.layout = func(gtx C) D {
+			return th.HFlex().AlignMiddle().
+				Flexed(1, th.Body1(field.tag.label).Layout).
+				Rigid(sw.Layout).
+				Layout(gtx)
+		}
+	default:
+		return fmt.Errorf("unknown widget %q for a bool", field.tag.widget)
+	}
+	return nil
+}
+
+// bindInt shows an integer as a slider or a number field
+func (f *StructForm) bindInt(field *formField, v reflect.Value) error {
+	th := f.window.Theme
+	s := newFieldSync(v, readInt, writeInt)
+	field.sync = s.sync
+	tag := field.tag
+	switch tag.widget {
+	case "", "slider":
+		slider := th.NewInt().SetValue(readInt(v)).Bind(s.value)
+		if tag.hasMin || tag.hasMax {
+			lo, hi := 0, 100
+			if tag.hasMin {
+				lo = int(tag.min)
+			}
+			if tag.hasMax {
+				hi = int(tag.max)
+			}
+			slider.SetRange(lo, hi)
+		}
+		field.widget = slider
+		field.layout = func(gtx C) D {
+			return f.sliderRow(gtx, field.tag.label, strconv.Itoa(slider.Value()), func(gtx C) D {
+				return slider.Layout(gtx, th)
+			})
+		}
+	case "number":
+		number := newNumberField(th, s.value, tag)
+		field.widget = number
+		field.layout = func(gtx C) D {
+			return th.HFlex().AlignMiddle().
+				Flexed(1, th.Body1(field.tag.label).Layout).
+				Rigid(number.Layout).
+				Layout(gtx)
+		}
+	default:
+		return fmt.Errorf("unknown widget %q for an integer", tag.widget)
+	}
+	return nil
+}
+
+// bindFloat shows a float as a slider
+func (f *StructForm) bindFloat(field *formField, v reflect.Value) error {
+	th := f.window.Theme
+	if field.tag.widget != "" && field.tag.widget != "slider" {
+		return fmt.Errorf("unknown widget %q for a float", field.tag.widget)
+	}
+	s := newFieldSync(v, func(v reflect.Value) float32 { return float32(v.Float()) },
+		func(v reflect.Value, x float32) { v.SetFloat(float64(x)) })
+	field.sync = s.sync
+	slider := th.NewFloat().SetValue(float32(v.Float())).Bind(s.value)
+	if field.tag.hasMin || field.tag.hasMax {
+		lo, hi := float32(0), float32(1)
+		if field.tag.hasMin {
+			lo = float32(field.tag.min)
+		}
+		if field.tag.hasMax {
+			hi = float32(field.tag.max)
+		}
+		slider.SetRange(lo, hi)
+	}
+	field.widget = slider
+	field.layout = func(gtx C) D {
+		value := strconv.FormatFloat(float64(slider.Value()), 'f', 2, 32)
+		return f.sliderRow(gtx, field.tag.label, value, func(gtx C) D {
+			return slider.Layout(gtx, th)
+		})
+	}
+	return nil
+}
+
+// bindEnum shows a string or integer field with options as radio buttons
+func (f *StructForm) bindEnum(field *formField, v reflect.Value) error {
+	if field.tag.widget != "" && field.tag.widget != "radio" {
+		return fmt.Errorf("unknown widget %q for options", field.tag.widget)
+	}
+	options := field.tag.options
+	read, write := readInt, writeInt
+	if v.Kind() == reflect.String {
+		read = func(v reflect.Value) int {
+			for i, option := range options {
+				if option == v.String() {
+					return i
+				}
+			}
+			return -1
+		}
+		write = func(v reflect.Value, i int) {
+			if i >= 0 && i < len(options) {
+				v.SetString(options[i])
+			}
+		}
+	}
+	s := newFieldSync(v, read, write)
+	field.sync = s.sync
+	group := f.window.NewRadioButtonGroup()
+	selected := read(v)
+	for i, option := range options {
+		group.AddButton(option, i == selected)
+	}
+	group.SetOnChange(func(i int, _ string) { s.value.Set(i) })
+	field.widget = group
+	th := f.window.Theme
+	field.layout = func(gtx C) D {
+		// Show changes to the field made by the program
+		if i := s.value.Get(); i >= 0 && i < len(options) {
+			if current, _ := group.GetSelected(); current != i {
+				group.SetSelected(i)
+			}
+		}
+		return th.VFlex().
+			Rigid(th.Body2(field.tag.label).Color(th.Colors.OnSurfaceVariant()).Layout).
+			Rigid(group.Layout).
+			Layout(gtx)
+	}
+	return nil
+}
+
+// bindColor shows a color.NRGBA as a color selector with a swatch of the color
+func (f *StructForm) bindColor(field *formField, v reflect.Value) error {
+	if field.tag.widget != "" && field.tag.widget != "color" {
+		return fmt.Errorf("unknown widget %q for a color", field.tag.widget)
+	}
+	th := f.window.Theme
+	read := func(v reflect.Value) color.NRGBA { return v.Interface().(color.NRGBA) }
+	s := newFieldSync(v, read, func(v reflect.Value, c color.NRGBA) {
+		// The selector has no alpha slider, so edits keep the field's alpha
+		c.A = read(v).A
+		v.Set(reflect.ValueOf(c))
+	})
+	field.sync = s.sync
+	selector := th.NewColorSelector().SetColor(read(v)).Bind(s.value)
+	field.widget = selector
+	field.layout = func(gtx C) D {
+		return th.VFlex().
+			Rigid(func(gtx C) D {
+				return th.HFlex().AlignMiddle().
+					Flexed(1, th.Body2(field.tag.label).Color(th.Colors.OnSurfaceVariant()).Layout).
+					Rigid(func(gtx C) D {
+						size := gtx.Dp(th.TextSize * 1.5)
+						rect := image.Rectangle{Max: image.Pt(size*2, size)}
+						paint.FillShape(gtx.Ops, s.value.Get(), clip.UniformRRect(rect, size/4).Op(gtx.Ops))
+						return D{Size: rect.Max}
+					}).
+					Layout(gtx)
+			}).
+			Rigid(func(gtx C) D { return selector.Layout(gtx, th) }).
+			Layout(gtx)
+	}
+	return nil
+}
+
+// sliderRow draws a label and the slider's value above the slider at full width
+func (f *StructForm) sliderRow(gtx C, label, value string, slider W) D {
+	th := f.window.Theme
+	return th.VFlex().
+		Rigid(func(gtx C) D {
+			return th.HFlex().
+				Flexed(1, th.Body2(label).Color(th.Colors.OnSurfaceVariant()).Layout).
+				Rigid(th.Body2(value).Layout).
+				Layout(gtx)
+		}).
+		Rigid(func(gtx C) D {
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return slider(gtx)
+		}).
+		Layout(gtx)
+}
+
+// validate checks a field's value against its tag
+func (field *formField) validate(v reflect.Value) error {
+	tag := field.tag
+	if tag.required && v.IsZero() {
+		return errors.New("required")
+	}
+	var n float64
+	switch {
+	case len(tag.options) > 0 || v.Type() == colorType:
+		return nil
+	case isIntKind(v.Kind()):
+		n = float64(readInt(v))
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		n = v.Float()
+	default:
+		return nil
+	}
+	if tag.hasMin && n < tag.min {
+		return fmt.Errorf("must be at least %v", tag.min)
+	}
+	if tag.hasMax && n > tag.max {
+		return fmt.Errorf("must be at most %v", tag.max)
+	}
+	return nil
+}
+
+// fieldSync keeps a struct field and the observable value its widget is bound to in step
+type fieldSync[T comparable] struct {
+	field reflect.Value
+	value *Value[T]
+	// version of the value last written to or read from the field
+	version uint64
+	read    func(reflect.Value) T
+	write   func(reflect.Value, T)
+}
+
+// newFieldSync creates a value holding the field's current contents
+func newFieldSync[T comparable](field reflect.Value, read func(reflect.Value) T, write func(reflect.Value, T)) *fieldSync[T] {
+	s := &fieldSync[T]{field: field, value: NewValue(read(field)), read: read, write: write}
+	_, s.version = s.value.load()
+	return s
+}
+
+// sync writes an edit made in the widget to the field, or else shows a change to the
+// field in the widget, and reports whether the widget edited the field
+func (s *fieldSync[T]) sync() bool {
+	value, version := s.value.load()
+	if version != s.version {
+		s.version = version
+		s.write(s.field, value)
+		return true
+	}
+	if current := s.read(s.field); current != value {
+		s.version, _ = s.value.set(current)
+	}
+	return false
+}
+
+// numberField edits an integer with decrement and increment buttons
+type numberField struct {
+	theme    *Theme
+	value    *Value[int]
+	min, max int
+	hasMin   bool
+	hasMax   bool
+	step     int
+	dec, inc *ButtonLayout
+}
+
+// newNumberField creates a number field for the value, limited to the tag's range
+func newNumberField(t *Theme, value *Value[int], tag formTag) *numberField {
+	n := &numberField{theme: t, value: value, min: int(tag.min), max: int(tag.max),
+		hasMin: tag.hasMin, hasMax: tag.hasMax, step: max(int(tag.step), 1)}
+	n.dec = t.TextButton("−").OnClick(func() { n.value.Update(func(v int) int { return n.clamp(v - n.step) }) })
+	n.inc = t.TextButton("+").OnClick(func() { n.value.Update(func(v int) int { return n.clamp(v + n.step) }) })
+	return n
+}
+
+// clamp limits v to the field's range
+func (n *numberField) clamp(v int) int {
+	if n.hasMin && v < n.min {
+		v = n.min
+	}
+	if n.hasMax && v > n.max {
+		v = n.max
+	}
+	return v
+}
+
+// Layout draws the value between its buttons, disabling a button at the end of the range
+func (n *numberField) Layout(gtx C) D {
+	t := n.theme
+	v := n.value.Get()
+	n.dec.Disabled(n.hasMin && v <= n.min)
+	n.inc.Disabled(n.hasMax && v >= n.max)
+	return t.HFlex().AlignMiddle().
+		Rigid(n.dec.Layout).
+		Rigid(func(gtx C) D {
+			// Wide enough for a few digits so the buttons stay put as the value changes
+			gtx.Constraints.Min.X = gtx.Dp(t.TextSize * 4)
+			return t.Body1(strconv.Itoa(v)).Alignment(text.Middle).Layout(gtx)
+		}).
+		Rigid(n.inc.Layout).
+		Layout(gtx)
+}
+
+// parseFormTag parses the options of a form tag
+func parseFormTag(tag string) (t formTag, err error) {
+	if tag == "-" {
+		t.skip = true
+		return
+	}
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "":
+		case "label":
+			t.label = value
+		case "widget":
+			t.widget = value
+		case "min", "max", "step":
+			var n float64
+			if n, err = strconv.ParseFloat(value, 64); err != nil {
+				return t, fmt.Errorf("invalid %s %q", key, value)
+			}
+			switch key {
+			case "min":
+				t.min, t.hasMin = n, true
+			case "max":
+				t.max, t.hasMax = n, true
+			default:
+				t.step = n
+			}
+		case "options":
+			t.options = strings.Split(value, "|")
+		case "required":
+			t.required = true
+		default:
+			return t, fmt.Errorf("unknown form option %q", key)
+		}
+	}
+	return
+}
+
+// fieldLabel splits a field name into words, e.g. "FontSize" becomes "Font size" and
+// "HTTPPort" becomes "HTTP port"
+func fieldLabel(name string) string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerBefore := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+		acronymEnd := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if unicode.IsUpper(runes[i]) && (lowerBefore || acronymEnd) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+	for i := 1; i < len(words); i++ {
+		if strings.ToUpper(words[i]) != words[i] {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// isIntKind reports whether k is a signed or unsigned integer kind
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Uint64
+}
+
+// readInt returns an integer field as an int
+func readInt(v reflect.Value) int {
+	if v.CanInt() {
+		return int(v.Int())
+	}
+	return int(v.Uint())
+}
+
+// writeInt sets an integer field, ignoring values it cannot hold
+func writeInt(v reflect.Value, n int) {
+	if v.CanInt() {
+		if !v.OverflowInt(int64(n)) {
+			v.SetInt(int64(n))
+		}
+	} else if n >= 0 && !v.OverflowUint(uint64(n)) {
+		v.SetUint(uint64(n))
+	}
+}
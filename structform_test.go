@@ -0,0 +1,184 @@
+package fromage
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"gio.mleku.dev/layout"
+	"gio.mleku.dev/op"
+)
+
+type formSettings struct {
+	Enabled  bool    `form:"widget=switch"`
+	Verbose  bool    `form:"label=Verbose logging"`
+	Volume   int     `form:"min=0,max=11"`
+	Retries  uint8   `form:"widget=number,min=1,max=5,required"`
+	Opacity  float64 `form:"min=0,max=1"`
+	Quality  string  `form:"options=Low|Medium|High"`
+	Level    int     `form:"options=Debug|Info|Warn"`
+	Accent   color.NRGBA
+	Internal string `form:"-"`
+	hidden   []int
+}
+
+func newFormTestWindow() *Window {
+	return &Window{Theme: newGoldenTheme()}
+}
+
+func TestStructFormFields(t *testing.T) {
+	s := formSettings{Retries: 3, Quality: "Medium", Accent: color.NRGBA{R: 255, A: 128}}
+	form, err := newFormTestWindow().NewStructForm(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, field := range form.fields {
+		names = append(names, field.name+"="+field.tag.label)
+	}
+	want := []string{"Enabled=Enabled", "Verbose=Verbose logging", "Volume=Volume", "Retries=Retries",
+		"Opacity=Opacity", "Quality=Quality", "Level=Level", "Accent=Accent"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected fields %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Expected field %s, got %s", want[i], names[i])
+		}
+	}
+	gtx := layout.Context{Ops: new(op.Ops), Constraints: layout.Exact(image.Pt(400, 800))}
+	form.Layout(gtx)
+}
+
+func TestStructFormTwoWay(t *testing.T) {
+	s := formSettings{Retries: 3, Quality: "Medium", Accent: color.NRGBA{R: 255, A: 128}}
+	form, err := newFormTestWindow().NewStructForm(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edited []string
+	form.OnChange(func(field string) { edited = append(edited, field) })
+
+	// Edits in the widgets are written to the struct
+	volume := form.Widget("Volume").(*Int)
+	volume.bound.push(7)
+	form.Sync()
+	if s.Volume != 7 || len(edited) != 1 || edited[0] != "Volume" {
+		t.Errorf("Expected the edit to reach the struct, got %d and %v", s.Volume, edited)
+	}
+
+	// Changes to the struct reach the widget without counting as edits
+	s.Volume = 2
+	form.Sync()
+	if v, ok := volume.bound.pull(); !ok || v != 2 || len(edited) != 1 {
+		t.Errorf("Expected the slider to show 2, got %d with edits %v", v, edited)
+	}
+
+	// Enums write the chosen option, or its index for integer fields
+	form.Widget("Quality").(*RadioButtonGroup).SetSelected(2)
+	form.Widget("Level").(*RadioButtonGroup).SetSelected(1)
+	form.Sync()
+	if s.Quality != "High" || s.Level != 1 {
+		t.Errorf("Expected High and 1, got %q and %d", s.Quality, s.Level)
+	}
+	s.Quality = "Low"
+	form.Sync()
+	form.Layout(layout.Context{Ops: new(op.Ops), Constraints: layout.Exact(image.Pt(400, 800))})
+	if i, _ := form.Widget("Quality").(*RadioButtonGroup).GetSelected(); i != 0 || s.Quality != "Low" {
+		t.Errorf("Expected the group to show Low, got option %d and %q", i, s.Quality)
+	}
+
+	// Colors keep the field's alpha
+	selector := form.Widget("Accent").(*ColorSelector)
+	selector.SetHue(2. / 3)
+	selector.updateColor()
+	form.Sync()
+	if s.Accent.B == 0 || s.Accent.R != 0 || s.Accent.A != 128 {
+		t.Errorf("Expected a blue accent with alpha 128, got %v", s.Accent)
+	}
+}
+
+func TestStructFormNumberField(t *testing.T) {
+	s := formSettings{Retries: 4}
+	form, err := newFormTestWindow().NewStructForm(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var number *numberField
+	for _, field := range form.fields {
+		if field.name == "Retries" {
+			number = field.widget.(*numberField)
+		}
+	}
+	number.inc.onClick()
+	number.inc.onClick()
+	form.Sync()
+	if s.Retries != 5 {
+		t.Errorf("Expected the increment to stop at the maximum 5, got %d", s.Retries)
+	}
+	number.dec.onClick()
+	form.Sync()
+	if s.Retries != 4 {
+		t.Errorf("Expected 4 after a decrement, got %d", s.Retries)
+	}
+}
+
+func TestStructFormValidate(t *testing.T) {
+	s := formSettings{Volume: 12, Retries: 3}
+	form, err := newFormTestWindow().NewStructForm(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fe *FieldError
+	if err = form.Validate(); !errors.As(err, &fe) || fe.Field != "Volume" {
+		t.Errorf("Expected Volume to be over its maximum, got %v", err)
+	}
+	s.Volume, s.Retries = 11, 0
+	if err = form.Validate(); !errors.As(err, &fe) || fe.Field != "Retries" {
+		t.Errorf("Expected Retries to be required, got %v", err)
+	}
+	s.Retries = 1
+	if err = form.Validate(); err != nil {
+		t.Errorf("Expected a valid struct, got %v", err)
+	}
+}
+
+func TestStructFormErrors(t *testing.T) {
+	w := newFormTestWindow()
+	if _, err := w.NewStructForm(formSettings{}); err == nil {
+		t.Error("Expected a struct value to be rejected")
+	}
+	var unsupported struct{ Names []string }
+	if _, err := w.NewStructForm(&unsupported); err == nil {
+		t.Error("Expected a slice field to be rejected")
+	}
+	var badTag struct {
+		On bool `form:"widget=slider"`
+	}
+	if _, err := w.NewStructForm(&badTag); err == nil {
+		t.Error("Expected a slider for a bool to be rejected")
+	}
+	var badOption struct {
+		N int `form:"maximum=3"`
+	}
+	var fe *FieldError
+	if _, err := w.NewStructForm(&badOption); !errors.As(err, &fe) || fe.Field != "N" {
+		t.Errorf("Expected an unknown option to name the field, got %v", err)
+	}
+}
+
+func TestFieldLabel(t *testing.T) {
+	for name, want := range map[string]string{
+		"Volume":     "Volume",
+		"FontSize":   "Font size",
+		"HTTPPort":   "HTTP port",
+		"MaxRetries": "Max retries",
+		"UseTLS":     "Use TLS",
+		"Level2Gain": "Level2 gain",
+	} {
+		if got := fieldLabel(name); got != want {
+			t.Errorf("Expected %q for %s, got %q", want, name, got)
+		}
+	}
+}
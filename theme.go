@@ -2,6 +2,8 @@ package fromage
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"gio.mleku.dev/text"
 	"gio.mleku.dev/unit"
@@ -9,12 +11,27 @@ import (
 )
 
 type Theme struct {
-	ctx       context.Context
-	Colors    *Colors
-	Shaper    *text.Shaper
-	TextSize  unit.Dp
-	Pool      *Pool
-	iconCache IconCache
+	ctx           context.Context
+	Colors        *Colors
+	Shaper        *text.Shaper
+	Fonts         *FontLibrary
+	TextSize      unit.Dp
+	Motion        *Motion
+	Typography    *Typography
+	Pool          *Pool
+	iconCache     IconCache
+	systemSchemes chan ColorScheme
+	// Stops the system preference watch started by FollowSystem
+	stopSystemWatch context.CancelFunc
+	// Stops the theme file watch started by WatchFile
+	stopFileWatch context.CancelFunc
+	themeFiles    chan *loadedTheme
+	fontUpdates   chan fontUpdate
+	fontsOnce     sync.Once
+	work          atomic.Pointer[WorkQueue]
+	workOnce      sync.Once
+	invalidate    atomic.Value
+	asyncs        []asyncWatcher
 }
 
 // Pool manages widget instances to avoid creating new ones on every frame
@@ -40,12 +57,14 @@ func NewThemeWithMode(
 	mode ThemeMode,
 ) *Theme {
 	return &Theme{
-		ctx:       ctx,
-		Colors:    NewColorsWithMode(mode),
-		Shaper:    shaper,
-		TextSize:  textSize,
-		Pool:      &Pool{},
-		iconCache: make(IconCache),
+		ctx:        ctx,
+		Colors:     NewColorsWithMode(mode),
+		Shaper:     shaper,
+		TextSize:   textSize,
+		Motion:     NewMotion(),
+		Typography: NewTypography(),
+		Pool:       &Pool{},
+		iconCache:  make(IconCache),
 	}
 }
 
@@ -54,20 +73,37 @@ func (t *Theme) ThemeMode() ThemeMode {
 	return t.Colors.ThemeMode()
 }
 
+// SetThemeMode switches the theme mode, cross-fading all role colors over the
+// motion ThemeTransition duration
 func (t *Theme) SetThemeMode(mode ThemeMode) {
+	t.applyTransition()
 	t.Colors.SetThemeMode(mode)
 }
 
+// ToggleTheme switches between light and dark mode with a cross-fade
 func (t *Theme) ToggleTheme() {
+	t.applyTransition()
 	t.Colors.ToggleTheme()
 }
 
+// SetColorVision simulates a color vision deficiency on all role colors with a cross-fade
+func (t *Theme) SetColorVision(vision ColorVision) {
+	t.applyTransition()
+	t.Colors.SetColorVision(vision)
+}
+
+// applyTransition configures the colors cross-fade from the current motion settings
+func (t *Theme) applyTransition() {
+	motion := t.motion()
+	t.Colors.SetTransition(motion.Duration(motion.ThemeTransition))
+}
+
 func (t *Theme) IsDark() bool {
-	return t.Colors.ThemeMode() == ThemeModeDark
+	return t.Colors.EffectiveMode().IsDark()
 }
 
 func (t *Theme) IsLight() bool {
-	return t.Colors.ThemeMode() == ThemeModeLight
+	return !t.Colors.EffectiveMode().IsDark()
 }
 
 // Pool methods
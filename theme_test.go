@@ -56,3 +56,21 @@ func TestThemeColors(t *testing.T) {
 		t.Errorf("Dark theme should have a dark background color")
 	}
 }
+
+func TestSurfaceTintHSVRoundTrip(t *testing.T) {
+	colors := NewColorsWithMode(ThemeModeLight)
+	for _, tint := range []string{"#263238", "#6750A4", "#FF8000", "#010203", "#FFFFFF"} {
+		want := hex(tint)
+		colors.SetSurfaceTint(want)
+		colors.SetSurfaceTintFromHSV(colors.GetSurfaceTintHSV())
+		if got := colors.GetSurfaceTint(); got != want {
+			t.Errorf("Expected %s to survive the HSV round trip, got %s", tint, ColorToHex(got))
+		}
+		// Setting the tone it already has must not darken the tint
+		_, _, v := colors.GetSurfaceTintHSV()
+		colors.SetSurfaceTintTone(v)
+		if got := colors.GetSurfaceTint(); got != want {
+			t.Errorf("Expected setting the same tone to keep %s, got %s", tint, ColorToHex(got))
+		}
+	}
+}
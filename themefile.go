@@ -0,0 +1,419 @@
+package fromage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/unit"
+)
+
+// ThemeFileVersion is the current theme file schema version
+const ThemeFileVersion = 1
+
+// ThemeFileError reports an invalid value in a theme file, naming the offending key
+// as a dotted path such as "light.onPrimary"
+type ThemeFileError struct {
+	Key string
+	Err error
+}
+
+func (e *ThemeFileError) Error() string { return fmt.Sprintf("theme file: %s: %v", e.Key, e.Err) }
+
+func (e *ThemeFileError) Unwrap() error { return e.Err }
+
+// themeFile is the on-disk theme schema. Colors are "#RRGGBB" strings keyed by the
+// lowerCamelCase palette or role name, e.g. "primary500" or "onPrimaryContainer". The
+// light, dark and high contrast sections hold role sets that replace the ones derived
+// from the palette.
+type themeFile struct {
+	Version           int                        `json:"version"`
+	Mode              string                     `json:"mode"`
+	TextSize          float32                    `json:"textSize"`
+	SurfaceTint       surfaceTintHSV             `json:"surfaceTint"`
+	MinContrast       float64                    `json:"minContrast,omitempty"`
+	Palette           map[string]string          `json:"palette"`
+	Light             map[string]string          `json:"light,omitempty"`
+	Dark              map[string]string          `json:"dark,omitempty"`
+	HighContrastLight map[string]string          `json:"highContrastLight,omitempty"`
+	HighContrastDark  map[string]string          `json:"highContrastDark,omitempty"`
+	Typography        map[string]json.RawMessage `json:"typography"`
+}
+
+// typeStyleFile is a TypeStyle in a theme file, with a CSS font weight (100 to 1000)
+type typeStyleFile struct {
+	Typeface      string  `json:"typeface"`
+	Weight        int     `json:"weight"`
+	Italic        bool    `json:"italic"`
+	Scale         float32 `json:"scale"`
+	LineHeight    float32 `json:"lineHeight"`
+	LetterSpacing float32 `json:"letterSpacing"`
+}
+
+// surfaceTintHSV is the surface tint as hue, saturation and value, all 0 to 1
+type surfaceTintHSV struct {
+	Hue        float32 `json:"hue"`
+	Saturation float32 `json:"saturation"`
+	Value      float32 `json:"value"`
+}
+
+// themeModeNames maps theme modes to their names in theme files
+var themeModeNames = map[ThemeMode]string{
+	ThemeModeLight: "light",
+	ThemeModeDark:  "dark",
+	ThemeModeAuto:  "auto",
+
+	ThemeModeHighContrastLight: "highContrastLight",
+	ThemeModeHighContrastDark:  "highContrastDark",
+}
+
+// roleSections returns the theme file sections of the modes that can have custom roles
+func (f *themeFile) roleSections() map[ThemeMode]*map[string]string {
+	return map[ThemeMode]*map[string]string{
+		ThemeModeLight:             &f.Light,
+		ThemeModeDark:              &f.Dark,
+		ThemeModeHighContrastLight: &f.HighContrastLight,
+		ThemeModeHighContrastDark:  &f.HighContrastDark,
+	}
+}
+
+// Save writes the theme settings as a versioned JSON theme file. Roles are only written
+// where SetRoles replaced them; the others are derived from the palette again on load.
+func (t *Theme) Save(w io.Writer) error {
+	f := themeFile{
+		Version:     ThemeFileVersion,
+		Mode:        themeModeNames[t.Colors.ThemeMode()],
+		TextSize:    float32(t.TextSize),
+		MinContrast: float64(t.Colors.MinContrast()),
+		Palette:     make(map[string]string),
+	}
+	f.SurfaceTint.Hue, f.SurfaceTint.Saturation, f.SurfaceTint.Value = t.Colors.GetSurfaceTintHSV()
+	palette := t.Colors.Palette()
+	palette.each(func(name string, c *color.NRGBA) { f.Palette[themeFileKey(name)] = ColorToHex(*c) })
+	for mode, section := range f.roleSections() {
+		*section = encodeRoles(t.Colors.customRoles, mode)
+	}
+	f.Typography = make(map[string]json.RawMessage)
+	var err error
+	t.typography().each(func(name string, s *TypeStyle) {
+		if err != nil {
+			return
+		}
+		f.Typography[themeFileKey(name)], err = json.Marshal(newTypeStyleFile(*s))
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// encodeRoles returns the theme file section of a mode's custom roles, nil if it has none
+func encodeRoles(custom map[ThemeMode]ColorRoles, mode ThemeMode) map[string]string {
+	roles, ok := custom[mode]
+	if !ok {
+		return nil
+	}
+	section := make(map[string]string)
+	roles.each(func(name string, c *color.NRGBA) { section[themeFileKey(name)] = ColorToHex(*c) })
+	return section
+}
+
+// SaveFile writes the theme settings to a JSON theme file
+func (t *Theme) SaveFile(path string) error {
+	var buf bytes.Buffer
+	if err := t.Save(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Load reads a JSON theme file and applies it to the theme. The file is fully validated
+// before anything is applied, so an error leaves the theme unchanged.
+func (t *Theme) Load(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	f, err := parseThemeFile(data)
+	if err != nil {
+		return err
+	}
+	t.applyThemeFile(f)
+	return nil
+}
+
+// LoadFile reads a JSON theme file from disk and applies it to the theme
+func (t *Theme) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return t.Load(file)
+}
+
+// loadedTheme is a validated theme file ready to be applied
+type loadedTheme struct {
+	mode        *ThemeMode
+	textSize    unit.Dp
+	tint        *surfaceTintHSV
+	minContrast float64
+	palette     *ColorPalette
+	typography  *Typography
+	roles       map[ThemeMode]ColorRoles
+}
+
+// parseThemeFile decodes and validates a theme file
+func parseThemeFile(data []byte) (*loadedTheme, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, &ThemeFileError{Key: "(root)", Err: err}
+	}
+	known := map[string]bool{"version": true, "mode": true, "textSize": true, "surfaceTint": true,
+		"minContrast": true, "palette": true, "light": true, "dark": true,
+		"highContrastLight": true, "highContrastDark": true, "typography": true}
+	for _, key := range sortedKeys(raw) {
+		if !known[key] {
+			return nil, &ThemeFileError{Key: key, Err: errors.New("unknown key")}
+		}
+	}
+	var f themeFile
+	for _, field := range []struct {
+		key string
+		dst any
+	}{
+		{"version", &f.Version}, {"mode", &f.Mode}, {"textSize", &f.TextSize},
+		{"surfaceTint", &f.SurfaceTint}, {"minContrast", &f.MinContrast},
+		{"palette", &f.Palette}, {"light", &f.Light}, {"dark", &f.Dark},
+		{"highContrastLight", &f.HighContrastLight}, {"highContrastDark", &f.HighContrastDark},
+		{"typography", &f.Typography},
+	} {
+		msg, ok := raw[field.key]
+		if !ok {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(msg))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(field.dst); err != nil {
+			return nil, &ThemeFileError{Key: field.key, Err: err}
+		}
+	}
+
+	if _, ok := raw["version"]; !ok {
+		return nil, &ThemeFileError{Key: "version", Err: errors.New("missing")}
+	}
+	if f.Version < 1 || f.Version > ThemeFileVersion {
+		return nil, &ThemeFileError{Key: "version",
+			Err: fmt.Errorf("unsupported version %d (supported: 1 to %d)", f.Version, ThemeFileVersion)}
+	}
+	l := &loadedTheme{textSize: unit.Dp(f.TextSize),
+		minContrast: f.MinContrast, roles: make(map[ThemeMode]ColorRoles)}
+	if f.Mode != "" {
+		found := false
+		for mode, name := range themeModeNames {
+			if name == f.Mode {
+				l.mode, found = &mode, true
+			}
+		}
+		if !found {
+			return nil, &ThemeFileError{Key: "mode", Err: fmt.Errorf("unknown mode %q", f.Mode)}
+		}
+	}
+	if _, ok := raw["textSize"]; ok && f.TextSize <= 0 {
+		return nil, &ThemeFileError{Key: "textSize", Err: fmt.Errorf("must be positive, got %v", f.TextSize)}
+	}
+	if _, ok := raw["surfaceTint"]; ok {
+		for _, c := range []struct {
+			key string
+			v   float32
+		}{{"hue", f.SurfaceTint.Hue}, {"saturation", f.SurfaceTint.Saturation}, {"value", f.SurfaceTint.Value}} {
+			if c.v < 0 || c.v > 1 {
+				return nil, &ThemeFileError{Key: "surfaceTint." + c.key, Err: fmt.Errorf("must be between 0 and 1, got %v", c.v)}
+			}
+		}
+		l.tint = &f.SurfaceTint
+	}
+	if f.MinContrast != 0 && (f.MinContrast < 1 || f.MinContrast > 21) {
+		return nil, &ThemeFileError{Key: "minContrast", Err: fmt.Errorf("must be 0 or between 1 and 21, got %v", f.MinContrast)}
+	}
+	if f.Palette != nil {
+		l.palette = &ColorPalette{}
+		if err := decodeColors("palette", f.Palette, l.palette.each); err != nil {
+			return nil, err
+		}
+	}
+	for mode, section := range f.roleSections() {
+		if *section == nil {
+			continue
+		}
+		var roles ColorRoles
+		if err := decodeColors(themeModeNames[mode], *section, roles.each); err != nil {
+			return nil, err
+		}
+		l.roles[mode] = roles
+	}
+	if f.Typography != nil {
+		var err error
+		if l.typography, err = decodeTypography(f.Typography); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// newTypeStyleFile converts a type style to its theme file form
+func newTypeStyleFile(s TypeStyle) typeStyleFile {
+	return typeStyleFile{
+		Typeface:      string(s.Font.Typeface),
+		Weight:        int(s.Font.Weight) + 400,
+		Italic:        s.Font.Style == font.Italic,
+		Scale:         s.Scale,
+		LineHeight:    s.LineHeight,
+		LetterSpacing: s.LetterSpacing,
+	}
+}
+
+// decodeTypography overlays the styles given in a theme file onto the default type scale
+func decodeTypography(styles map[string]json.RawMessage) (*Typography, error) {
+	ty := NewTypography()
+	seen := make(map[string]bool)
+	var err error
+	ty.each(func(name string, s *TypeStyle) {
+		key := themeFileKey(name)
+		seen[key] = true
+		msg, ok := styles[key]
+		if err != nil || !ok {
+			return
+		}
+		path := "typography." + key
+		sf := newTypeStyleFile(*s)
+		dec := json.NewDecoder(bytes.NewReader(msg))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&sf); err != nil {
+			err = &ThemeFileError{Key: path, Err: err}
+			return
+		}
+		switch {
+		case sf.Weight < 100 || sf.Weight > 1000:
+			err = &ThemeFileError{Key: path + ".weight", Err: fmt.Errorf("must be between 100 and 1000, got %d", sf.Weight)}
+		case sf.Scale <= 0:
+			err = &ThemeFileError{Key: path + ".scale", Err: fmt.Errorf("must be positive, got %v", sf.Scale)}
+		case sf.LineHeight < 0:
+			err = &ThemeFileError{Key: path + ".lineHeight", Err: fmt.Errorf("must not be negative, got %v", sf.LineHeight)}
+		}
+		if err != nil {
+			return
+		}
+		s.Font = font.Font{Typeface: font.Typeface(sf.Typeface), Weight: font.Weight(sf.Weight - 400)}
+		if sf.Italic {
+			s.Font.Style = font.Italic
+		}
+		s.Scale, s.LineHeight, s.LetterSpacing = sf.Scale, sf.LineHeight, sf.LetterSpacing
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(styles) {
+		if !seen[key] {
+			return nil, &ThemeFileError{Key: "typography." + key, Err: errors.New("unknown style")}
+		}
+	}
+	return ty, nil
+}
+
+// decodeColors sets every color visited by each from the section, requiring all of them
+// to be present and rejecting unknown keys
+func decodeColors(section string, values map[string]string, each func(func(string, *color.NRGBA))) error {
+	seen := make(map[string]bool)
+	var err error
+	each(func(name string, c *color.NRGBA) {
+		key := themeFileKey(name)
+		seen[key] = true
+		if err != nil {
+			return
+		}
+		v, ok := values[key]
+		if !ok {
+			err = &ThemeFileError{Key: section + "." + key, Err: errors.New("missing")}
+			return
+		}
+		if *c, ok = parseHexColor(v); !ok {
+			err = &ThemeFileError{Key: section + "." + key, Err: fmt.Errorf("invalid color %q, want \"#RRGGBB\"", v)}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(values) {
+		if !seen[key] {
+			return &ThemeFileError{Key: section + "." + key, Err: errors.New("unknown key")}
+		}
+	}
+	return nil
+}
+
+// applyThemeFile applies a validated theme file, cross-fading to the new roles
+func (t *Theme) applyThemeFile(l *loadedTheme) {
+	c := t.Colors
+	from := *c.current()
+	if l.textSize > 0 {
+		t.TextSize = l.textSize
+	}
+	if l.typography != nil {
+		t.Typography = l.typography
+	}
+	if l.palette != nil {
+		c.palette = *l.palette
+	}
+	if l.tint != nil {
+		c.surfaceTint = c.hsvToRgb(l.tint.Hue*360, l.tint.Saturation, l.tint.Value)
+	}
+	c.minContrast = l.minContrast
+	// Role sets equal to the derived ones, as older versions saved them, stay derived so
+	// that later palette and surface tint changes still apply
+	c.customRoles = nil
+	for mode, roles := range l.roles {
+		if roles != c.RolesFor(mode) {
+			if c.customRoles == nil {
+				c.customRoles = make(map[ThemeMode]ColorRoles)
+			}
+			c.customRoles[mode] = roles
+		}
+	}
+	if l.mode != nil {
+		c.themeMode = *l.mode
+	}
+	t.applyTransition()
+	c.initRoles()
+	c.startTransition(from)
+}
+
+// themeFileKey converts a Go field name to its lowerCamelCase theme file key
+func themeFileKey(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// parseHexColor parses a "#RRGGBB" color, reporting whether it was valid
+func parseHexColor(s string) (color.NRGBA, bool) {
+	c := hex(s)
+	return c, c.A == 255
+}
+
+// sortedKeys returns the keys of a map in sorted order, for deterministic error reporting
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,180 @@
+package fromage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gio.mleku.dev/unit"
+)
+
+func TestThemeSaveLoadRoundTrip(t *testing.T) {
+	src := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(18), ThemeModeDark)
+	src.Colors = NewColorsFromSeedWithMode(0x6750A4, ThemeModeDark)
+	src.Colors.SetSurfaceTintFromHSV(0.6, 0.4, 0.2)
+	src.Typography.Typeface("Inter").Scale(1.25)
+
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := src.SaveFile(path); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	dst := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	dst.SetReducedMotion(true)
+	if err := dst.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if dst.TextSize != 18 {
+		t.Errorf("Expected text size 18, got %v", dst.TextSize)
+	}
+	if dst.ThemeMode() != ThemeModeDark {
+		t.Errorf("Expected dark mode, got %v", dst.ThemeMode())
+	}
+	if dst.Colors.Roles() != src.Colors.Roles() {
+		t.Error("Expected dark roles to round-trip")
+	}
+	if dst.Colors.RolesFor(ThemeModeLight) != src.Colors.RolesFor(ThemeModeLight) {
+		t.Error("Expected light roles to round-trip")
+	}
+	if dst.Colors.Palette() != src.Colors.Palette() {
+		t.Error("Expected palette to round-trip")
+	}
+	if *dst.Typography != *src.Typography {
+		t.Error("Expected typography to round-trip")
+	}
+	if dst.Colors.MinContrast() != ContrastAA {
+		t.Errorf("Expected min contrast AA, got %v", dst.Colors.MinContrast())
+	}
+}
+
+func TestThemeLoadValidation(t *testing.T) {
+	var saved bytes.Buffer
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	// Custom roles give the file light and dark sections
+	th.Colors.SetRoles(ThemeModeLight, th.Colors.RolesFor(ThemeModeLight))
+	th.Colors.SetRoles(ThemeModeDark, th.Colors.RolesFor(ThemeModeDark))
+	if err := th.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+
+	// edit decodes the saved file, applies a change and re-encodes it
+	edit := func(change func(m map[string]any)) string {
+		var m map[string]any
+		if err := json.Unmarshal(saved.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		change(m)
+		b, _ := json.Marshal(m)
+		return string(b)
+	}
+	tests := []struct {
+		name string
+		file string
+		key  string
+	}{
+		{"syntax", "{", "(root)"},
+		{"missing version", edit(func(m map[string]any) { delete(m, "version") }), "version"},
+		{"future version", edit(func(m map[string]any) { m["version"] = 99 }), "version"},
+		{"unknown key", edit(func(m map[string]any) { m["colour"] = "red" }), "colour"},
+		{"bad mode", edit(func(m map[string]any) { m["mode"] = "dim" }), "mode"},
+		{"bad text size", edit(func(m map[string]any) { m["textSize"] = -1 }), "textSize"},
+		{"bad tint", edit(func(m map[string]any) { m["surfaceTint"].(map[string]any)["value"] = 2 }), "surfaceTint.value"},
+		{"bad color", edit(func(m map[string]any) { m["light"].(map[string]any)["onPrimary"] = "#12345" }), "light.onPrimary"},
+		{"missing role", edit(func(m map[string]any) { delete(m["dark"].(map[string]any), "surface") }), "dark.surface"},
+		{"unknown role", edit(func(m map[string]any) { m["dark"].(map[string]any)["onSurfaceTint"] = "#000000" }), "dark.onSurfaceTint"},
+		{"wrong type", edit(func(m map[string]any) { m["palette"] = "blue" }), "palette"},
+	}
+	for _, tt := range tests {
+		before := th.Colors.Roles()
+		err := th.Load(strings.NewReader(tt.file))
+		var fileErr *ThemeFileError
+		if !errors.As(err, &fileErr) {
+			t.Errorf("%s: expected ThemeFileError, got %v", tt.name, err)
+			continue
+		}
+		if fileErr.Key != tt.key {
+			t.Errorf("%s: expected key %q, got %q (%v)", tt.name, tt.key, fileErr.Key, err)
+		}
+		if th.Colors.Roles() != before || th.TextSize != 16 {
+			t.Errorf("%s: expected theme to be unchanged after a failed load", tt.name)
+		}
+	}
+}
+
+func TestThemeLoadKeepsRolesDerived(t *testing.T) {
+	src := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	custom := src.Colors.RolesFor(ThemeModeDark)
+	custom.Primary = hex("#FF0000")
+	src.Colors.SetRoles(ThemeModeDark, custom)
+	contrast := src.Colors.RolesFor(ThemeModeHighContrastDark)
+	contrast.Primary = hex("#00FF00")
+	src.Colors.SetRoles(ThemeModeHighContrastDark, contrast)
+	var saved bytes.Buffer
+	if err := src.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(saved.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["light"]; ok {
+		t.Error("Expected no light section without custom light roles")
+	}
+
+	dst := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	dst.SetReducedMotion(true)
+	if err := dst.Load(bytes.NewReader(saved.Bytes())); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if dst.Colors.RolesFor(ThemeModeDark).Primary != custom.Primary {
+		t.Error("Expected the custom dark roles to round-trip")
+	}
+	if dst.Colors.RolesFor(ThemeModeHighContrastDark) != src.Colors.RolesFor(ThemeModeHighContrastDark) {
+		t.Error("Expected the custom high contrast dark roles to round-trip")
+	}
+	before := dst.Colors.Roles()
+	dst.Colors.SetSurfaceTintFromHSV(0.3, 0.8, 0.5)
+	if dst.Colors.Roles() == before {
+		t.Error("Expected the surface tint to change the derived light roles after a load")
+	}
+
+	// A file listing the derived roles, as older versions wrote, keeps them derived
+	derived := src.Colors.RolesFor(ThemeModeLight)
+	m["light"] = map[string]any{}
+	derived.each(func(name string, c *color.NRGBA) {
+		m["light"].(map[string]any)[themeFileKey(name)] = ColorToHex(*c)
+	})
+	old, _ := json.Marshal(m)
+	if err := dst.Load(bytes.NewReader(old)); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := dst.Colors.customRoles[ThemeModeLight]; ok {
+		t.Error("Expected light roles equal to the derived ones to stay derived")
+	}
+}
+
+func TestThemeLoadPartial(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	th.SetReducedMotion(true)
+	before := th.Colors.Roles()
+	if err := th.Load(strings.NewReader(`{"version": 1, "mode": "dark"}`)); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if th.TextSize != 16 {
+		t.Errorf("Expected text size to be kept, got %v", th.TextSize)
+	}
+	if !th.IsDark() || th.Colors.Roles() == before {
+		t.Error("Expected theme to switch to palette-derived dark roles")
+	}
+	if err := th.Load(strings.NewReader(`{"version": 1, "textSize": 18}`)); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !th.IsDark() {
+		t.Error("Expected a file without a mode to keep the theme mode")
+	}
+}
@@ -0,0 +1,94 @@
+package fromage
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"lol.mleku.dev/log"
+)
+
+// DefaultThemeWatchInterval is how often a watched theme file is checked for changes
+const DefaultThemeWatchInterval = 500 * time.Millisecond
+
+// WatchFile loads a theme file and then polls it for changes, re-applying it whenever it is
+// modified. Files are parsed and validated on the watcher goroutine and applied on the UI
+// goroutine by BeginFrame; onChange is called after each successful parse so the caller can
+// request a new frame. An invalid edit is logged and the last good theme is kept. Watching
+// stops when the theme's context is done, or when WatchFile is called again.
+func (t *Theme) WatchFile(path string, interval time.Duration, onChange func()) (err error) {
+	if t.stopFileWatch != nil {
+		t.stopFileWatch()
+		t.stopFileWatch = nil
+	}
+	if err = t.LoadFile(path); err != nil {
+		return
+	}
+	var last os.FileInfo
+	if last, err = os.Stat(path); err != nil {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultThemeWatchInterval
+	}
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, t.stopFileWatch = context.WithCancel(ctx)
+	pending := make(chan *loadedTheme, 1)
+	t.themeFiles = pending
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil || (info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+			data, err := os.ReadFile(path)
+			if err != nil {
+				log.E.F("reading theme file %s: %v", path, err)
+				continue
+			}
+			loaded, err := parseThemeFile(data)
+			if err != nil {
+				log.E.F("%s: %v", path, err)
+				continue
+			}
+			// Keep only the latest version if the UI has not drained it yet
+			select {
+			case <-pending:
+			default:
+			}
+			pending <- loaded
+			if onChange != nil {
+				onChange()
+			}
+		}
+	}()
+	return
+}
+
+// WatchThemeFile makes the window's theme follow a theme file, invalidating the window on changes
+func (w *Window) WatchThemeFile(path string) error {
+	return w.Theme.WatchFile(path, DefaultThemeWatchInterval, w.Invalidate)
+}
+
+// drainThemeFiles applies a pending theme file reload, if any
+func (t *Theme) drainThemeFiles() {
+	if t.themeFiles == nil {
+		return
+	}
+	select {
+	case loaded := <-t.themeFiles:
+		t.applyThemeFile(loaded)
+	default:
+	}
+}
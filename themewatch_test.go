@@ -0,0 +1,107 @@
+package fromage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/unit"
+)
+
+func TestThemeWatchFile(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1, "mode": "light", "textSize": 14}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	th := NewThemeWithMode(ctx, NewColors, nil, unit.Dp(16), ThemeModeDark)
+	th.SetReducedMotion(true)
+	changed := make(chan struct{}, 1)
+	if err := th.WatchFile(path, 5*time.Millisecond, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("WatchFile failed: %v", err)
+	}
+	if !th.IsLight() || th.TextSize != 14 {
+		t.Fatalf("Expected the file to be applied immediately, got mode %v size %v", th.ThemeMode(), th.TextSize)
+	}
+
+	// An invalid edit is ignored
+	if err := os.WriteFile(path, []byte(`{"version": 1, "mode": "sepia"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+		t.Fatal("Expected an invalid theme file not to be reported as a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 1, "mode": "dark", "textSize": 20}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the edit to be picked up")
+	}
+	if th.TextSize != 14 {
+		t.Error("Expected the reload to wait for the next frame")
+	}
+	th.drainThemeFiles()
+	if !th.IsDark() || th.TextSize != 20 {
+		t.Errorf("Expected reloaded dark theme at size 20, got mode %v size %v", th.ThemeMode(), th.TextSize)
+	}
+}
+
+func TestThemeWatchFileReplaced(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.json"), filepath.Join(dir, "second.json")
+	for _, path := range []string{first, second} {
+		if err := os.WriteFile(path, []byte(`{"version": 1, "textSize": 14}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	changed := make(chan string, 2)
+	if err := th.WatchFile(first, 5*time.Millisecond, func() { changed <- first }); err != nil {
+		t.Fatalf("WatchFile failed: %v", err)
+	}
+	if err := th.WatchFile(second, 5*time.Millisecond, func() { changed <- second }); err != nil {
+		t.Fatalf("WatchFile failed: %v", err)
+	}
+	defer th.stopFileWatch()
+
+	// The first watch has stopped, so only the second file is followed
+	if err := os.WriteFile(first, []byte(`{"version": 1, "textSize": 18}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case path := <-changed:
+		t.Fatalf("Expected the replaced watch of %s to have stopped", path)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := os.WriteFile(second, []byte(`{"version": 1, "textSize": 20}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case path := <-changed:
+		if path != second {
+			t.Errorf("Expected a change of %s, got %s", second, path)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the edit of the watched file to be picked up")
+	}
+}
+
+func TestThemeWatchFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.json")
+	if err := os.WriteFile(path, []byte(`{"mode": "dark"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	if err := th.WatchFile(path, 0, nil); err == nil {
+		t.Error("Expected an invalid initial theme file to be reported")
+	}
+}
@@ -0,0 +1,230 @@
+package fromage
+
+import (
+	"image/color"
+	"math"
+)
+
+// TonalPalette is a hue and chroma in the OKLCH color space from which any tone
+// (perceptual lightness, 0 = black to 100 = white) can be derived. Tones use the
+// CIE L* scale as Material Design 3 does, so a tone difference of 50 or more always
+// gives a WCAG contrast ratio of at least 4.5:1.
+type TonalPalette struct {
+	// Hue is the OKLCH hue angle in degrees
+	Hue float64
+	// Chroma is the OKLCH chroma, reduced per tone as needed to stay inside sRGB
+	Chroma float64
+}
+
+// NewTonalPalette creates a tonal palette from an OKLCH hue and chroma
+func NewTonalPalette(hue, chroma float64) TonalPalette {
+	return TonalPalette{Hue: math.Mod(hue+360, 360), Chroma: chroma}
+}
+
+// TonalPaletteFromColor creates a tonal palette with the hue and chroma of the given color
+func TonalPaletteFromColor(c color.NRGBA) TonalPalette {
+	_, chroma, hue := toOKLCH(c)
+	return NewTonalPalette(hue, chroma)
+}
+
+// Tone returns the palette color with the given CIE L* tone (0 to 100)
+func (p TonalPalette) Tone(tone float64) color.NRGBA {
+	if tone <= 0 {
+		return color.NRGBA{A: 255}
+	}
+	if tone >= 100 {
+		return color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	target := toneToY(tone)
+	lo, hi := 0.0, 1.0
+	var r, g, b float64
+	for i := 0; i < 32; i++ {
+		l := (lo + hi) / 2
+		r, g, b = clipOKLCH(l, p.Chroma, p.Hue)
+		if luminanceLinear(r, g, b) < target {
+			lo = l
+		} else {
+			hi = l
+		}
+	}
+	return color.NRGBA{R: encodeSRGB(r), G: encodeSRGB(g), B: encodeSRGB(b), A: 255}
+}
+
+// SeedScheme holds the key tonal palettes derived from a single seed color
+type SeedScheme struct {
+	Primary        TonalPalette
+	Secondary      TonalPalette
+	Tertiary       TonalPalette
+	Error          TonalPalette
+	Neutral        TonalPalette
+	NeutralVariant TonalPalette
+}
+
+// Minimum OKLCH chroma for the primary palette so dull seeds still give a colorful theme
+const seedMinPrimaryChroma = 0.12
+
+// NewSeedScheme derives the primary, secondary, tertiary, neutral and neutral-variant
+// palettes from a seed color in the manner of the Material Design 3 "tonal spot" scheme
+func NewSeedScheme(seed uint32) SeedScheme {
+	_, chroma, hue := toOKLCH(rgb(seed))
+	return SeedScheme{
+		Primary:        NewTonalPalette(hue, math.Max(chroma, seedMinPrimaryChroma)),
+		Secondary:      NewTonalPalette(hue, 0.05),
+		Tertiary:       NewTonalPalette(hue+60, 0.08),
+		Error:          NewTonalPalette(27, 0.17),
+		Neutral:        NewTonalPalette(hue, 0.012),
+		NeutralVariant: NewTonalPalette(hue, 0.025),
+	}
+}
+
+// paletteTones maps the ColorPalette steps onto tones, so that the light and dark role
+// assignments keep at least 50 tones between every role and its on-role
+var paletteTones = [11]float64{98, 90, 80, 70, 60, 40, 35, 30, 25, 10, 5}
+
+// fill sets the 50 to 950 steps of a palette from a tonal palette
+func (p TonalPalette) fill(steps ...*color.NRGBA) {
+	for i, c := range steps {
+		*c = p.Tone(paletteTones[i])
+	}
+}
+
+// NewColorsFromSeed generates a light theme from one seed color, as
+// NewColorsFromSeedWithMode does
+func NewColorsFromSeed(seed uint32) *Colors {
+	return NewColorsFromSeedWithMode(seed, ThemeModeLight)
+}
+
+// NewColorsFromSeedWithMode generates complete light and dark role sets from one seed color,
+// with every role and on-role pair meeting WCAG AA (4.5:1) contrast
+func NewColorsFromSeedWithMode(seed uint32, mode ThemeMode) *Colors {
+	scheme := NewSeedScheme(seed)
+	theme := &Colors{
+		themeMode:   mode,
+		surfaceTint: scheme.Neutral.Tone(12),
+		minContrast: float64(ContrastAA),
+	}
+
+	theme.initSeedPalette(scheme)
+	theme.initRoles()
+
+	return theme
+}
+
+// Initialize palette from seed-derived tonal palettes
+func (t *Colors) initSeedPalette(s SeedScheme) {
+	p := &t.palette
+	s.Primary.fill(&p.Primary50, &p.Primary100, &p.Primary200, &p.Primary300, &p.Primary400,
+		&p.Primary500, &p.Primary600, &p.Primary700, &p.Primary800, &p.Primary900, &p.Primary950)
+	s.Secondary.fill(&p.Secondary50, &p.Secondary100, &p.Secondary200, &p.Secondary300, &p.Secondary400,
+		&p.Secondary500, &p.Secondary600, &p.Secondary700, &p.Secondary800, &p.Secondary900, &p.Secondary950)
+	s.Tertiary.fill(&p.Tertiary50, &p.Tertiary100, &p.Tertiary200, &p.Tertiary300, &p.Tertiary400,
+		&p.Tertiary500, &p.Tertiary600, &p.Tertiary700, &p.Tertiary800, &p.Tertiary900, &p.Tertiary950)
+	s.Error.fill(&p.Error50, &p.Error100, &p.Error200, &p.Error300, &p.Error400,
+		&p.Error500, &p.Error600, &p.Error700, &p.Error800, &p.Error900, &p.Error950)
+	s.Neutral.fill(&p.Neutral50, &p.Neutral100, &p.Neutral200, &p.Neutral300, &p.Neutral400,
+		&p.Neutral500, &p.Neutral600, &p.Neutral700, &p.Neutral800, &p.Neutral900, &p.Neutral950)
+	s.NeutralVariant.fill(&p.NeutralVariant50, &p.NeutralVariant100, &p.NeutralVariant200,
+		&p.NeutralVariant300, &p.NeutralVariant400, &p.NeutralVariant500, &p.NeutralVariant600,
+		&p.NeutralVariant700, &p.NeutralVariant800, &p.NeutralVariant900, &p.NeutralVariant950)
+}
+
+// relativeLuminance returns the WCAG relative luminance of a color (0 to 1)
+func relativeLuminance(c color.NRGBA) float64 {
+	return luminanceLinear(decodeSRGB(c.R), decodeSRGB(c.G), decodeSRGB(c.B))
+}
+
+// colorTone returns the CIE L* tone of a color (0 to 100)
+func colorTone(c color.NRGBA) float64 {
+	return yToTone(relativeLuminance(c))
+}
+
+// luminanceLinear returns the relative luminance of linear sRGB components
+func luminanceLinear(r, g, b float64) float64 {
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// toneToY converts a CIE L* tone to relative luminance
+func toneToY(tone float64) float64 {
+	f := (tone + 16) / 116
+	if f*f*f > 216.0/24389.0 {
+		return f * f * f
+	}
+	return tone * 27.0 / 24389.0
+}
+
+// yToTone converts relative luminance to a CIE L* tone
+func yToTone(y float64) float64 {
+	if y > 216.0/24389.0 {
+		return 116*math.Cbrt(y) - 16
+	}
+	return y * 24389.0 / 27.0
+}
+
+// decodeSRGB converts an 8-bit sRGB component to linear light
+func decodeSRGB(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// encodeSRGB converts a linear light component to 8-bit sRGB
+func encodeSRGB(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
+
+// toOKLCH converts a color to OKLCH lightness (0 to 1), chroma and hue in degrees
+func toOKLCH(c color.NRGBA) (l, chroma, hue float64) {
+	r, g, b := decodeSRGB(c.R), decodeSRGB(c.G), decodeSRGB(c.B)
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a := 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	bb := 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	chroma = math.Hypot(a, bb)
+	hue = math.Mod(math.Atan2(bb, a)*180/math.Pi+360, 360)
+	return
+}
+
+// fromOKLCH converts OKLCH to linear sRGB components, which may fall outside 0 to 1
+func fromOKLCH(l, chroma, hue float64) (r, g, b float64) {
+	h := hue * math.Pi / 180
+	a, bb := chroma*math.Cos(h), chroma*math.Sin(h)
+	lc := l + 0.3963377774*a + 0.2158037573*bb
+	mc := l - 0.1055613458*a - 0.0638541728*bb
+	sc := l - 0.0894841775*a - 1.2914855480*bb
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+	r = 4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc
+	g = -1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc
+	b = -0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc
+	return
+}
+
+// clipOKLCH converts OKLCH to linear sRGB, reducing chroma until the color is in gamut
+func clipOKLCH(l, chroma, hue float64) (r, g, b float64) {
+	inGamut := func(r, g, b float64) bool {
+		const eps = 1e-6
+		return r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	}
+	if r, g, b = fromOKLCH(l, chroma, hue); inGamut(r, g, b) {
+		return
+	}
+	lo, hi := 0.0, chroma
+	for i := 0; i < 24; i++ {
+		mid := (lo + hi) / 2
+		if inGamut(fromOKLCH(l, mid, hue)) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	r, g, b = fromOKLCH(l, lo, hue)
+	return math.Max(0, math.Min(1, r)), math.Max(0, math.Min(1, g)), math.Max(0, math.Min(1, b))
+}
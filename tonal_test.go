@@ -0,0 +1,62 @@
+package fromage
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestTonalPaletteTones(t *testing.T) {
+	palette := TonalPaletteFromColor(rgb(0x6750A4))
+
+	if c := palette.Tone(0); c != (color.NRGBA{A: 255}) {
+		t.Errorf("Expected tone 0 to be black, got %v", c)
+	}
+	if c := palette.Tone(100); c != (color.NRGBA{R: 255, G: 255, B: 255, A: 255}) {
+		t.Errorf("Expected tone 100 to be white, got %v", c)
+	}
+	for _, tone := range []float64{10, 25, 40, 50, 80, 90, 98} {
+		got := colorTone(palette.Tone(tone))
+		if math.Abs(got-tone) > 1 {
+			t.Errorf("Expected tone %v, got %.2f", tone, got)
+		}
+	}
+}
+
+func TestTonalPaletteKeepsHue(t *testing.T) {
+	seed := rgb(0x2E7D32)
+	_, _, want := toOKLCH(seed)
+	palette := TonalPaletteFromColor(seed)
+	_, _, got := toOKLCH(palette.Tone(50))
+	if math.Abs(got-want) > 3 {
+		t.Errorf("Expected hue near %.1f, got %.1f", want, got)
+	}
+}
+
+func TestColorsFromSeedContrast(t *testing.T) {
+	seeds := []uint32{0x6750A4, 0xFFEB3B, 0x00BCD4, 0xF44336, 0x808080, 0x000000, 0xFFFFFF, 0x4CAF50}
+	for _, seed := range seeds {
+		for _, mode := range []ThemeMode{ThemeModeLight, ThemeModeDark} {
+			colors := NewColorsFromSeedWithMode(seed, mode)
+			roles := colors.Roles()
+			for _, pair := range rolePairs {
+				bg, fg := *roles.field(pair[0]), *roles.field(pair[1])
+				if r := ContrastRatio(fg, bg); r < 4.5 {
+					t.Errorf("Seed %06X mode %v: %s on %s contrast %.2f below 4.5", seed, mode, pair[1], pair[0], r)
+				}
+			}
+		}
+	}
+}
+
+func TestColorsFromSeedModeSwitch(t *testing.T) {
+	colors := NewColorsFromSeed(0x6750A4)
+	light := colors.Primary()
+	colors.SetThemeMode(ThemeModeDark)
+	if colors.Primary() == light {
+		t.Error("Expected dark primary to differ from light primary")
+	}
+	if colorTone(colors.Primary()) <= colorTone(light) {
+		t.Error("Expected dark primary to be a lighter tone than light primary")
+	}
+}
@@ -0,0 +1,114 @@
+package fromage
+
+import (
+	"image/color"
+	"time"
+
+	"gio.mleku.dev/op"
+)
+
+// SetTransition sets the duration of the role color cross-fade on theme mode changes (0 = instant)
+func (t *Colors) SetTransition(d time.Duration) *Colors {
+	t.transition = d
+	return t
+}
+
+// Transition returns the duration of the role color cross-fade on theme mode changes
+func (t *Colors) Transition() time.Duration { return t.transition }
+
+// IsTransitioning returns true while a theme cross-fade is in progress
+func (t *Colors) IsTransitioning() bool { return t.transitioning && !t.stale() }
+
+// Roles returns a copy of the role colors currently being shown
+func (t *Colors) Roles() ColorRoles { return *t.current() }
+
+// TargetRoles returns a copy of the role colors of the current theme mode, which a
+// running cross-fade is heading to
+func (t *Colors) TargetRoles() ColorRoles { return t.roles }
+
+// Update advances the theme cross-fade to the given time and returns true while it is still running
+func (t *Colors) Update(now time.Time) bool {
+	if !t.transitioning {
+		return false
+	}
+	if t.stale() {
+		t.transitioning = false
+		return false
+	}
+	// The fade starts at the first frame after the change, on the frame clock
+	if t.transitionStart.IsZero() {
+		t.transitionStart = now
+	}
+	elapsed := max(now.Sub(t.transitionStart), 0)
+	if elapsed >= t.transition {
+		t.transitioning = false
+		return false
+	}
+	progress := EaseOutQuad(clamp01(float32(elapsed) / float32(t.transition)))
+	t.shown = lerpRoles(t.transitionFrom, t.roles, progress)
+	return true
+}
+
+// startTransition begins a cross-fade from the given roles to the current target roles
+func (t *Colors) startTransition(from ColorRoles) {
+	if t.transition <= 0 {
+		t.transitioning = false
+		return
+	}
+	t.transitionFrom = from
+	t.shown = from
+	t.transitionStart = time.Time{}
+	t.transitionRequested = time.Now()
+	t.transitioning = true
+}
+
+// stale reports whether a cross-fade got no frame for longer than its duration, so it
+// shows the target roles rather than starting late
+func (t *Colors) stale() bool {
+	return t.transitionStart.IsZero() && time.Since(t.transitionRequested) >= t.transition
+}
+
+// current returns the roles to display. It only reads the cross-fade state, which
+// Update advances once per frame from Theme.BeginFrame.
+func (t *Colors) current() *ColorRoles {
+	if !t.transitioning || t.stale() {
+		return &t.roles
+	}
+	return &t.shown
+}
+
+// lerpRoles interpolates every role color between two role sets
+func lerpRoles(from, to ColorRoles, progress float32) ColorRoles {
+	var src []color.NRGBA
+	from.each(func(_ string, c *color.NRGBA) { src = append(src, *c) })
+	i := 0
+	to.each(func(_ string, c *color.NRGBA) {
+		*c = lerpColor(src[i], *c, progress)
+		i++
+	})
+	return to
+}
+
+// lerpColor interpolates between two colors based on progress, clamped to 0.0 to 1.0
+func lerpColor(from, to color.NRGBA, progress float32) color.NRGBA {
+	progress = clamp01(progress)
+	return color.NRGBA{
+		R: uint8(float32(from.R) + (float32(to.R)-float32(from.R))*progress),
+		G: uint8(float32(from.G) + (float32(to.G)-float32(from.G))*progress),
+		B: uint8(float32(from.B) + (float32(to.B)-float32(from.B))*progress),
+		A: uint8(float32(from.A) + (float32(to.A)-float32(from.A))*progress),
+	}
+}
+
+// BeginFrame advances theme-wide state at the start of a frame, delivers finished background
+// jobs, and requests another frame while a theme transition is running
+func (t *Theme) BeginFrame(gtx C) {
+	t.drainSystemSchemes()
+	t.drainThemeFiles()
+	t.drainFonts()
+	t.drainWork()
+	t.sweepAsync()
+	if t.Colors != nil && t.Colors.Update(gtx.Now) {
+		gtx.Execute(op.InvalidateCmd{})
+	}
+}
@@ -0,0 +1,136 @@
+package fromage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/text"
+	"gio.mleku.dev/unit"
+)
+
+func TestColorsInstantModeChange(t *testing.T) {
+	colors := NewColorsWithMode(ThemeModeLight)
+	dark := NewColorsWithMode(ThemeModeDark)
+
+	// Without a transition duration the roles switch immediately
+	colors.SetThemeMode(ThemeModeDark)
+	if colors.IsTransitioning() {
+		t.Error("Expected no transition when duration is zero")
+	}
+	if colors.Background() != dark.Background() {
+		t.Errorf("Expected dark background %v, got %v", dark.Background(), colors.Background())
+	}
+}
+
+func TestColorsTransition(t *testing.T) {
+	light := NewColorsWithMode(ThemeModeLight)
+	dark := NewColorsWithMode(ThemeModeDark)
+
+	colors := NewColorsWithMode(ThemeModeLight).SetTransition(250 * time.Millisecond)
+	colors.SetThemeMode(ThemeModeDark)
+
+	if !colors.IsTransitioning() {
+		t.Fatal("Expected transition to be running after mode change")
+	}
+	if colors.ThemeMode() != ThemeModeDark {
+		t.Errorf("Expected theme mode to switch immediately, got %v", colors.ThemeMode())
+	}
+	if colors.Background() != light.Background() {
+		t.Errorf("Expected background to start at light value, got %v", colors.Background())
+	}
+
+	// The first frame starts the fade on the frame clock
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !colors.Update(start) || colors.Background() != light.Background() {
+		t.Errorf("Expected the fade to start at the light background, got %v", colors.Background())
+	}
+
+	// Halfway through, the background should be between light and dark
+	if !colors.Update(start.Add(125 * time.Millisecond)) {
+		t.Error("Expected transition to still be running halfway through")
+	}
+	mid := colors.Background()
+	if mid.R >= light.Background().R || mid.R <= dark.Background().R {
+		t.Errorf("Expected intermediate background, got %v", mid)
+	}
+
+	// After the duration the target colors are shown
+	if colors.Update(start.Add(250 * time.Millisecond)) {
+		t.Error("Expected transition to be finished")
+	}
+	if colors.Background() != dark.Background() {
+		t.Errorf("Expected dark background after transition, got %v", colors.Background())
+	}
+}
+
+func TestColorsTransitionClockSkew(t *testing.T) {
+	light := NewColorsWithMode(ThemeModeLight)
+	colors := NewColorsWithMode(ThemeModeLight).SetTransition(250 * time.Millisecond)
+	colors.SetThemeMode(ThemeModeDark)
+
+	// A frame clock running behind the first frame's time must not extrapolate
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	colors.Update(start)
+	if !colors.Update(start.Add(-time.Hour)) {
+		t.Error("Expected the transition to keep running")
+	}
+	if colors.Background() != light.Background() {
+		t.Errorf("Expected the start color for a time before the fade, got %v", colors.Background())
+	}
+	if got := lerpColor(light.Background(), light.Primary(), -3); got != light.Background() {
+		t.Errorf("Expected progress below 0 to clamp to the start color, got %v", got)
+	}
+}
+
+func TestThemeTransitionReducedMotion(t *testing.T) {
+	th := NewThemeWithMode(context.TODO(), NewColors, text.NewShaper(), unit.Dp(16), ThemeModeLight)
+
+	th.ToggleTheme()
+	if !th.Colors.IsTransitioning() {
+		t.Error("Expected theme toggle to start a transition")
+	}
+
+	th.SetReducedMotion(true)
+	th.ToggleTheme()
+	if th.Colors.IsTransitioning() {
+		t.Error("Expected reduced motion to switch colors instantly")
+	}
+	if th.Colors.Background() != NewColorsWithMode(ThemeModeLight).Background() {
+		t.Error("Expected light background after instant toggle")
+	}
+}
+
+func TestSwitchThemeColorsDuringTransition(t *testing.T) {
+	th := NewThemeWithMode(context.TODO(), NewColors, text.NewShaper(), unit.Dp(16), ThemeModeLight)
+	sw := th.Switch(true)
+
+	// The toggle starts a cross-fade still showing the light colors
+	th.ToggleTheme()
+	sw.UpdateThemeColors(time.Now())
+	if want := NewColorsWithMode(ThemeModeDark).OnBackground(); sw.background != want {
+		t.Errorf("Expected the switch to take the dark theme's color %v, got %v", want, sw.background)
+	}
+}
+
+func TestColorsReadsDoNotAdvanceTransition(t *testing.T) {
+	light := NewColorsWithMode(ThemeModeLight)
+	dark := NewColorsWithMode(ThemeModeDark)
+	colors := NewColorsWithMode(ThemeModeLight).SetTransition(250 * time.Millisecond)
+	colors.SetThemeMode(ThemeModeDark)
+	if colors.TargetRoles().Background != dark.Background() || colors.Background() != light.Background() {
+		t.Errorf("Expected the target to be dark while the light colors show")
+	}
+
+	// A fade that got no frame for its whole duration shows the target without changing state
+	colors.transitionRequested = time.Now().Add(-time.Second)
+	if colors.Background() != dark.Background() || colors.IsTransitioning() {
+		t.Errorf("Expected a stale fade to show the target, got %v", colors.Background())
+	}
+	if !colors.transitioning {
+		t.Error("Expected reading colors to leave the fade for Update to finish")
+	}
+	if colors.Update(time.Now()) || colors.transitioning {
+		t.Error("Expected Update to finish the stale fade")
+	}
+}
@@ -0,0 +1,114 @@
+package fromage
+
+import (
+	"reflect"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/unit"
+)
+
+// TypeStyle is one text style of the type scale
+type TypeStyle struct {
+	// Font is the typeface, weight and style (an empty typeface uses the shaper's default)
+	Font font.Font
+	// Scale is the text size relative to the theme's TextSize (Material sizes assume 16)
+	Scale float32
+	// LineHeight is the line height as a multiple of the text size (0 = shaper default)
+	LineHeight float32
+	// LetterSpacing is the tracking in em. It is kept for theme files and exports; Gio's
+	// text shaper does not apply tracking.
+	LetterSpacing float32
+}
+
+// Size returns the text size of the style for a base text size
+func (s TypeStyle) Size(base unit.Dp) unit.Sp {
+	return unit.Sp(float32(base) * s.Scale)
+}
+
+// Typography holds the Material Design 3 type scale
+type Typography struct {
+	DisplayLarge   TypeStyle
+	DisplayMedium  TypeStyle
+	DisplaySmall   TypeStyle
+	HeadlineLarge  TypeStyle
+	HeadlineMedium TypeStyle
+	HeadlineSmall  TypeStyle
+	TitleLarge     TypeStyle
+	TitleMedium    TypeStyle
+	TitleSmall     TypeStyle
+	BodyLarge      TypeStyle
+	BodyMedium     TypeStyle
+	BodySmall      TypeStyle
+	LabelLarge     TypeStyle
+	LabelMedium    TypeStyle
+	LabelSmall     TypeStyle
+}
+
+// defaultTypography is used by themes that were created without a type scale
+var defaultTypography = NewTypography()
+
+// m3Style builds a style from Material's sp size, line height and tracking for a 16sp base
+func m3Style(weight font.Weight, size, lineHeight, tracking float32) TypeStyle {
+	return TypeStyle{
+		Font:          font.Font{Weight: weight},
+		Scale:         size / 16,
+		LineHeight:    lineHeight / size,
+		LetterSpacing: tracking / size,
+	}
+}
+
+// NewTypography creates the Material Design 3 default type scale
+func NewTypography() *Typography {
+	return &Typography{
+		DisplayLarge:   m3Style(font.Normal, 57, 64, -0.25),
+		DisplayMedium:  m3Style(font.Normal, 45, 52, 0),
+		DisplaySmall:   m3Style(font.Normal, 36, 44, 0),
+		HeadlineLarge:  m3Style(font.Normal, 32, 40, 0),
+		HeadlineMedium: m3Style(font.Normal, 28, 36, 0),
+		HeadlineSmall:  m3Style(font.Normal, 24, 32, 0),
+		TitleLarge:     m3Style(font.Normal, 22, 28, 0),
+		TitleMedium:    m3Style(font.Medium, 16, 24, 0.15),
+		TitleSmall:     m3Style(font.Medium, 14, 20, 0.1),
+		BodyLarge:      m3Style(font.Normal, 16, 24, 0.5),
+		BodyMedium:     m3Style(font.Normal, 14, 20, 0.25),
+		BodySmall:      m3Style(font.Normal, 12, 16, 0.4),
+		LabelLarge:     m3Style(font.Medium, 14, 20, 0.1),
+		LabelMedium:    m3Style(font.Medium, 12, 16, 0.5),
+		LabelSmall:     m3Style(font.Medium, 11, 16, 0.5),
+	}
+}
+
+// Typeface sets the typeface of every style
+func (ty *Typography) Typeface(typeface font.Typeface) *Typography {
+	ty.each(func(_ string, s *TypeStyle) { s.Font.Typeface = typeface })
+	return ty
+}
+
+// Scale multiplies the size of every style by the given factor
+func (ty *Typography) Scale(factor float32) *Typography {
+	ty.each(func(_ string, s *TypeStyle) { s.Scale *= factor })
+	return ty
+}
+
+// each calls fn with the field name and a pointer to every style in the type scale
+func (ty *Typography) each(fn func(name string, s *TypeStyle)) {
+	v := reflect.ValueOf(ty).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		fn(v.Type().Field(i).Name, v.Field(i).Addr().Interface().(*TypeStyle))
+	}
+}
+
+// Theme typography methods
+
+// typography returns the theme's type scale, falling back to the defaults
+func (t *Theme) typography() *Typography {
+	if t == nil || t.Typography == nil {
+		return defaultTypography
+	}
+	return t.Typography
+}
+
+// Styled creates a label with the given type style
+func (t *Theme) Styled(style TypeStyle, text string) *Label {
+	return t.NewLabel().Text(text).Style(style)
+}
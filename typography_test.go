@@ -0,0 +1,87 @@
+package fromage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gio.mleku.dev/font"
+	"gio.mleku.dev/unit"
+)
+
+func TestTypographyDefaults(t *testing.T) {
+	ty := NewTypography()
+	if got := ty.DisplayLarge.Size(16); got != 57 {
+		t.Errorf("Expected display large at 57sp for a 16 base, got %v", got)
+	}
+	if got := ty.BodyMedium.Size(16); got != 14 {
+		t.Errorf("Expected body medium at 14sp for a 16 base, got %v", got)
+	}
+	if ty.LabelLarge.Font.Weight != font.Medium {
+		t.Errorf("Expected label large to be medium weight, got %v", ty.LabelLarge.Font.Weight)
+	}
+	if got := ty.BodyLarge.LineHeight * 16; got != 24 {
+		t.Errorf("Expected body large line height of 24sp, got %v", got)
+	}
+
+	ty.Typeface("Inter").Scale(2)
+	if ty.TitleSmall.Font.Typeface != "Inter" || ty.TitleSmall.Size(16) != 28 {
+		t.Errorf("Expected typeface and scale to apply to every style, got %+v", ty.TitleSmall)
+	}
+}
+
+func TestLabelTypography(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(20), ThemeModeLight)
+	th.Typography.DisplayLarge.Font.Typeface = "Serif"
+
+	// H1 keeps its original size, in the display large font
+	h1 := th.H1("Title")
+	if h1.textSize != 40 || h1.font.Typeface != "Serif" || h1.lineHeight != 0 {
+		t.Errorf("Expected H1 at 40sp in the display large font, got size %v font %+v line height %v",
+			h1.textSize, h1.font, h1.lineHeight)
+	}
+	if caption := th.Caption("small"); caption.textSize != 10 {
+		t.Errorf("Expected caption at 10sp for a 20 base, got %v", caption.textSize)
+	}
+	th.Typography.BodySmall.Scale *= 1.5
+	if caption := th.Caption("small"); caption.textSize != 15 {
+		t.Errorf("Expected caption to follow a scaled body small style to 15sp, got %v", caption.textSize)
+	}
+	if body := th.Body1("text"); body.textSize != 20 || body.lineHeight != 0 {
+		t.Errorf("Expected body at 20sp with the default line height, got %v, %v", body.textSize, body.lineHeight)
+	}
+	display := th.Styled(th.Typography.DisplayLarge, "Title")
+	if display.textSize != th.Typography.DisplayLarge.Size(20) || display.lineHeight != th.Typography.DisplayLarge.LineHeight {
+		t.Errorf("Expected the display large style, got size %v line height %v", display.textSize, display.lineHeight)
+	}
+
+	// Themes built without a type scale fall back to the defaults
+	bare := &Theme{TextSize: 16, Colors: NewColors()}
+	if body := bare.Body1("text"); body.textSize != 16 {
+		t.Errorf("Expected default body size 16sp, got %v", body.textSize)
+	}
+}
+
+func TestThemeFileTypography(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	err := th.Load(strings.NewReader(`{"version": 1, "typography": {"bodyLarge": {"typeface": "Inter", "weight": 700, "italic": true}}}`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	body := th.Typography.BodyLarge
+	if body.Font.Typeface != "Inter" || body.Font.Weight != font.Bold || body.Font.Style != font.Italic {
+		t.Errorf("Expected bold italic Inter body, got %+v", body.Font)
+	}
+	if body.Scale != 1 {
+		t.Errorf("Expected unspecified fields to keep their defaults, got scale %v", body.Scale)
+	}
+
+	err = th.Load(strings.NewReader(`{"version": 1, "typography": {"labelSmall": {"weight": 50}}}`))
+	if fileErr, ok := err.(*ThemeFileError); !ok || fileErr.Key != "typography.labelSmall.weight" {
+		t.Errorf("Expected weight error at typography.labelSmall.weight, got %v", err)
+	}
+	err = th.Load(strings.NewReader(`{"version": 1, "typography": {"subtitle": {}}}`))
+	if fileErr, ok := err.(*ThemeFileError); !ok || fileErr.Key != "typography.subtitle" {
+		t.Errorf("Expected unknown style error at typography.subtitle, got %v", err)
+	}
+}
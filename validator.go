@@ -0,0 +1,61 @@
+package fromage
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Validator checks a value and returns an error whose message is shown under the field
+// when the value is invalid. Any func(T) error can be used as a custom validator; the
+// built-in ones use lowercase messages, as Go errors do.
+type Validator[T any] func(value T) error
+
+// AsyncValidator checks a value off the UI goroutine, for example against a server. The
+// context is cancelled when the value changes before the check finishes.
+type AsyncValidator[T any] func(ctx context.Context, value T) error
+
+// Required rejects the zero value
+func Required[T comparable]() Validator[T] {
+	return func(value T) error {
+		var zero T
+		if value == zero {
+			return errors.New("required")
+		}
+		return nil
+	}
+}
+
+// Range rejects values outside [lo, hi]
+func Range[T cmp.Ordered](lo, hi T) Validator[T] {
+	return func(value T) error {
+		if value < lo || value > hi {
+			return fmt.Errorf("must be between %v and %v", lo, hi)
+		}
+		return nil
+	}
+}
+
+// Pattern rejects strings that do not match the regular expression, with the given
+// message. It panics if the expression does not compile, like regexp.MustCompile.
+func Pattern(expr, message string) Validator[string] {
+	re := regexp.MustCompile(expr)
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// Check rejects values for which ok returns false, with the given message
+func Check[T any](ok func(T) bool, message string) Validator[T] {
+	return func(value T) error {
+		if !ok(value) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package fromage
+
+import (
+	"context"
+	"sync"
+)
+
+// Value is a goroutine-safe observable value. Widgets bound to it show its current value
+// and write user edits back; any goroutine may Set it, which notifies subscribers and wakes
+// the windows of the themes it invalidates so bound widgets redraw.
+type Value[T comparable] struct {
+	mx sync.Mutex
+	// value is the current value
+	value T
+	// version increases on every change
+	version uint64
+	// subscribers receive the latest value after each change
+	subscribers map[chan T]struct{}
+	// themes whose windows are invalidated on changes
+	themes []*Theme
+}
+
+// Signal is another name for Value
+type Signal[T comparable] = Value[T]
+
+// NewValue creates an observable value
+func NewValue[T comparable](initial T) *Value[T] {
+	return &Value[T]{value: initial, version: 1}
+}
+
+// Get returns the current value
+func (v *Value[T]) Get() T {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	return v.value
+}
+
+// Version returns a number that increases every time the value changes
+func (v *Value[T]) Version() uint64 {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	return v.version
+}
+
+// Set changes the value and reports whether it was different
+func (v *Value[T]) Set(value T) bool {
+	_, changed := v.set(value)
+	return changed
+}
+
+// Update changes the value to the result of fn applied to it, atomically
+func (v *Value[T]) Update(fn func(T) T) bool {
+	v.mx.Lock()
+	value := fn(v.value)
+	v.mx.Unlock()
+	// A concurrent Set between the two locks wins; Update is not a compare-and-swap
+	return v.Set(value)
+}
+
+// Subscribe returns a channel that receives the value after each change. A slow reader
+// only sees the latest value. The channel is closed when ctx is done.
+func (v *Value[T]) Subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, 1)
+	v.mx.Lock()
+	if v.subscribers == nil {
+		v.subscribers = make(map[chan T]struct{})
+	}
+	v.subscribers[ch] = struct{}{}
+	v.mx.Unlock()
+	go func() {
+		<-ctx.Done()
+		v.mx.Lock()
+		delete(v.subscribers, ch)
+		close(ch)
+		v.mx.Unlock()
+	}()
+	return ch
+}
+
+// Invalidates makes changes to the value wake the window the theme is attached to
+func (v *Value[T]) Invalidates(t *Theme) *Value[T] {
+	if t == nil {
+		return v
+	}
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	for _, existing := range v.themes {
+		if existing == t {
+			return v
+		}
+	}
+	v.themes = append(v.themes, t)
+	return v
+}
+
+// set changes the value, notifying subscribers and windows, and returns the new version
+func (v *Value[T]) set(value T) (version uint64, changed bool) {
+	v.mx.Lock()
+	if v.value == value {
+		version = v.version
+		v.mx.Unlock()
+		return
+	}
+	v.value = value
+	v.version++
+	version = v.version
+	for ch := range v.subscribers {
+		// Replace a value the subscriber has not read yet
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- value
+	}
+	themes := v.themes
+	v.mx.Unlock()
+	for _, t := range themes {
+		t.requestFrame()
+	}
+	return version, true
+}
+
+// load returns the value with its version
+func (v *Value[T]) load() (T, uint64) {
+	v.mx.Lock()
+	defer v.mx.Unlock()
+	return v.value, v.version
+}
+
+// binding connects a widget's value to a Value
+type binding[T comparable] struct {
+	value *Value[T]
+	// version is the version last shown or written by the widget
+	version uint64
+}
+
+// bind attaches the binding to a value; the widget picks the value up on its next pull
+func (b *binding[T]) bind(v *Value[T]) {
+	b.value, b.version = v, 0
+}
+
+// pull returns the bound value if it changed since the widget last saw it
+func (b *binding[T]) pull() (value T, ok bool) {
+	if b.value == nil {
+		return
+	}
+	value, version := b.value.load()
+	if version == b.version {
+		return
+	}
+	b.version = version
+	return value, true
+}
+
+// push writes a user edit to the bound value
+func (b *binding[T]) push(value T) {
+	if b.value == nil {
+		return
+	}
+	b.version, _ = b.value.set(value)
+}
+
+// Theme value methods
+
+// requestFrame wakes the window the theme was attached to with NewWindow, from any goroutine
+func (t *Theme) requestFrame() {
+	if fn, ok := t.invalidate.Load().(func()); ok {
+		fn()
+	}
+}
@@ -0,0 +1,147 @@
+package fromage
+
+import (
+	"context"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"gio.mleku.dev/unit"
+	"gio.mleku.dev/widget"
+)
+
+func TestValueSetAndVersion(t *testing.T) {
+	v := NewValue(1)
+	version := v.Version()
+	if v.Set(1) || v.Version() != version {
+		t.Error("Expected setting the same value to be a no-op")
+	}
+	if !v.Set(2) || v.Get() != 2 || v.Version() != version+1 {
+		t.Errorf("Expected a change to 2, got %d at version %d", v.Get(), v.Version())
+	}
+	v.Update(func(n int) int { return n * 10 })
+	if v.Get() != 20 {
+		t.Errorf("Expected update to 20, got %d", v.Get())
+	}
+}
+
+func TestValueSubscribe(t *testing.T) {
+	v := NewValue("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := v.Subscribe(ctx)
+	v.Set("b")
+	v.Set("c")
+	// A slow reader sees only the latest value
+	if got := <-ch; got != "c" {
+		t.Errorf("Expected the latest value c, got %q", got)
+	}
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Expected no further values")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the channel to close when the context is done")
+	}
+	v.Set("d")
+}
+
+func TestValueInvalidatesFromGoroutines(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	var mx sync.Mutex
+	frames := 0
+	th.invalidate.Store(func() { mx.Lock(); frames++; mx.Unlock() })
+	v := NewValue(0).Invalidates(th).Invalidates(th)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func() { defer wg.Done(); v.Set(i) }()
+	}
+	wg.Wait()
+	mx.Lock()
+	defer mx.Unlock()
+	if frames == 0 || frames > 10 {
+		t.Errorf("Expected one frame request per change, got %d", frames)
+	}
+}
+
+func TestWidgetBindings(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+
+	checked := NewValue(false)
+	cb := th.NewCheckbox(false).Bind(checked)
+	checked.Set(true)
+	if v, ok := cb.bound.pull(); !ok || !v {
+		t.Error("Expected the checkbox to pick up the new value")
+	}
+	if _, ok := cb.bound.pull(); ok {
+		t.Error("Expected no change on the second pull")
+	}
+	// A user edit is written back without being pulled again
+	cb.bound.push(false)
+	if checked.Get() {
+		t.Error("Expected the edit to be written to the value")
+	}
+	if _, ok := cb.bound.pull(); ok {
+		t.Error("Expected the widget's own edit not to be pulled back")
+	}
+
+	level := NewValue(5)
+	slider := th.NewInt().Bind(level)
+	if v, ok := slider.bound.pull(); !ok || v != 5 {
+		t.Errorf("Expected a newly bound slider to pull the value, got %d", v)
+	}
+
+	tint := NewValue(color.NRGBA{R: 255, A: 255})
+	cs := th.NewColorSelector().Bind(tint)
+	cs.SetHue(1. / 3)
+	cs.updateColor()
+	if got := tint.Get(); got.G == 0 || got.R != 0 {
+		t.Errorf("Expected the selector's green to be written back, got %v", got)
+	}
+}
+
+func TestBindWakesBeforeFirstFrame(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	frames := 0
+	th.invalidate.Store(func() { frames++ })
+
+	f, i, c, s := NewValue[float32](0), NewValue(0), NewValue(color.NRGBA{}), NewValue("")
+	th.NewFloat().Bind(f)
+	th.NewInt().Bind(i)
+	th.NewColorSelector().Bind(c)
+	th.BindEditor(&widget.Editor{}, s)
+	s.Set("x")
+	f.Set(0.5)
+	i.Set(3)
+	c.Set(color.NRGBA{R: 1, A: 255})
+	if frames != 4 {
+		t.Errorf("Expected every bound value to wake the window before any layout, got %d frames", frames)
+	}
+}
+
+func TestBoundEditor(t *testing.T) {
+	th := NewThemeWithMode(context.Background(), NewColors, nil, unit.Dp(16), ThemeModeLight)
+	name := NewValue("Ada")
+	editor := th.BindEditor(&widget.Editor{}, name)
+
+	editor.Sync()
+	if editor.Text() != "Ada" {
+		t.Errorf("Expected the editor to show the value, got %q", editor.Text())
+	}
+	// Typing is written to the value
+	editor.SetText("Ada L")
+	editor.Sync()
+	if name.Get() != "Ada L" {
+		t.Errorf("Expected the edit to be written to the value, got %q", name.Get())
+	}
+	// A change from elsewhere replaces the text
+	name.Set("Grace")
+	editor.Sync()
+	if editor.Text() != "Grace" {
+		t.Errorf("Expected the editor to show the new value, got %q", editor.Text())
+	}
+}
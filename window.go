@@ -2,16 +2,23 @@ package fromage
 
 import (
 	"gio.mleku.dev/app"
+	"gio.mleku.dev/op"
+	"lol.mleku.dev/chk"
 )
 
 type Window struct {
 	*app.Window
 	opts []app.Option
 	*Theme
+	// screenshots lets RunFrames render a screenshot requested by the environment
+	screenshots bool
 }
 
 func NewWindow(th *Theme) *Window {
-	return &Window{Window: &app.Window{}, Theme: th}
+	w := &Window{Window: &app.Window{}, Theme: th}
+	// Background job results wake the window so BeginFrame can deliver them
+	th.invalidate.Store(w.Invalidate)
+	return w
 }
 
 // Invalidate requests a new frame to be drawn
@@ -27,3 +34,28 @@ func (w *Window) Run(fn func()) {
 		w.Window.Run(fn)
 	}
 }
+
+// RunFrames runs the window's event loop, laying out each frame with frame after
+// resetting the widget pool and advancing theme state. With AllowScreenshots, a
+// screenshot requested by the environment is rendered instead.
+func (w *Window) RunFrames(frame func(gtx C)) {
+	if w.screenshotFromEnv(frame) {
+		return
+	}
+	w.Run(func() {
+		var ops op.Ops
+		for {
+			switch e := w.Event().(type) {
+			case app.DestroyEvent:
+				chk.E(e.Err)
+				return
+			case app.FrameEvent:
+				gtx := app.NewContext(&ops, e)
+				w.Theme.Pool.Reset()
+				w.Theme.BeginFrame(gtx)
+				frame(gtx)
+				e.Frame(gtx.Ops)
+			}
+		}
+	})
+}
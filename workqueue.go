@@ -0,0 +1,257 @@
+package fromage
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"lol.mleku.dev/log"
+)
+
+// Job is a unit of background work. It should return promptly once ctx is done.
+type Job func(ctx context.Context) (any, error)
+
+// PanicError is the error reported for a job that panicked
+type PanicError struct {
+	// Value passed to panic
+	Value any
+	// Stack of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("job panicked: %v", e.Value) }
+
+// Task is a handle to a submitted job
+type Task struct {
+	// id identifies the job in log messages
+	id uint64
+	// ctx is the job's context, derived from the queue's
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is set once the job's result has been delivered
+	done atomic.Bool
+}
+
+// ID returns the task's sequence number
+func (t *Task) ID() uint64 { return t.id }
+
+// Cancel cancels the job's context. A job that has not started yet is skipped and
+// completes with context.Canceled.
+func (t *Task) Cancel() { t.cancel() }
+
+// Done reports whether the job's result has been delivered on the UI goroutine
+func (t *Task) Done() bool { return t.done.Load() }
+
+// queuedJob is a job waiting for a worker
+type queuedJob struct {
+	task     *Task
+	job      Job
+	complete func(value any, err error)
+}
+
+// WorkQueue runs jobs on a pool of worker goroutines and hands their results back to the
+// UI goroutine. Workers, queued jobs and running jobs are all bound to the queue's
+// context: when it is done the workers stop and every job sees the cancellation.
+//
+// Results wait in a channel until Drain runs their completion callbacks, which happens in
+// Theme.BeginFrame for the theme's queue, so callbacks may touch widget state freely.
+// onResult is called from the worker after each result is queued, so the window can be
+// woken to draw the frame that drains it.
+type WorkQueue struct {
+	ctx context.Context
+	// submit hands jobs to the dispatcher, which feeds them to idle workers
+	submit chan queuedJob
+	work   chan queuedJob
+	// results holds completion callbacks waiting for the UI goroutine
+	results chan func()
+	// onResult is called when a result is ready
+	onResult func()
+	// nextID numbers the tasks
+	nextID atomic.Uint64
+	// pending counts jobs submitted but not yet drained
+	pending atomic.Int64
+	// running tracks the dispatcher and workers until the context is done
+	running sync.WaitGroup
+}
+
+// NewWorkQueue starts a work queue with the given number of workers (0 = GOMAXPROCS)
+// that runs until ctx is done
+func NewWorkQueue(ctx context.Context, workers int, onResult func()) *WorkQueue {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	q := &WorkQueue{
+		ctx:      ctx,
+		submit:   make(chan queuedJob),
+		work:     make(chan queuedJob),
+		results:  make(chan func(), 64),
+		onResult: onResult,
+	}
+	q.running.Add(workers + 1)
+	go q.dispatch()
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+// Submit queues a job. complete is called with its result on the UI goroutine when the
+// queue is drained; it may be nil. A job submitted after the queue's context is done
+// completes with the context's error.
+func (q *WorkQueue) Submit(job Job, complete func(value any, err error)) *Task {
+	ctx, cancel := context.WithCancel(q.ctx)
+	task := &Task{id: q.nextID.Add(1), ctx: ctx, cancel: cancel}
+	q.pending.Add(1)
+	queued := queuedJob{task: task, job: job, complete: complete}
+	select {
+	case q.submit <- queued:
+	case <-q.ctx.Done():
+		q.deliver(queued, nil, q.ctx.Err())
+	}
+	return task
+}
+
+// Drain runs the completion callbacks of finished jobs and returns how many ran. It must
+// be called on the UI goroutine.
+func (q *WorkQueue) Drain() (n int) {
+	for {
+		select {
+		case complete := <-q.results:
+			complete()
+			n++
+		default:
+			return
+		}
+	}
+}
+
+// Pending returns the number of jobs submitted whose results have not been drained
+func (q *WorkQueue) Pending() int { return int(q.pending.Load()) }
+
+// Wait blocks until the queue has stopped after its context is done and every queued job
+// has been completed with the context's error
+func (q *WorkQueue) Wait() { q.running.Wait() }
+
+// dispatch buffers submitted jobs without limit, so Submit never blocks the UI goroutine
+// on busy workers, and feeds them to workers in order
+func (q *WorkQueue) dispatch() {
+	defer q.running.Done()
+	var queue []queuedJob
+	for {
+		var work chan queuedJob
+		var next queuedJob
+		if len(queue) > 0 {
+			work, next = q.work, queue[0]
+		}
+		select {
+		case <-q.ctx.Done():
+			for _, queued := range queue {
+				q.deliver(queued, nil, q.ctx.Err())
+			}
+			return
+		case queued := <-q.submit:
+			queue = append(queue, queued)
+		case work <- next:
+			queue = queue[1:]
+		}
+	}
+}
+
+// worker runs jobs until the queue's context is done
+func (q *WorkQueue) worker() {
+	defer q.running.Done()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case queued := <-q.work:
+			value, err := q.run(queued)
+			q.deliver(queued, value, err)
+		}
+	}
+}
+
+// run runs a job, recovering a panic as a PanicError
+func (q *WorkQueue) run(queued queuedJob) (value any, err error) {
+	task := queued.task
+	if err = task.ctx.Err(); err != nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			perr := &PanicError{Value: r, Stack: debug.Stack()}
+			log.E.F("work queue task %d: %v\n%s", task.id, perr, perr.Stack)
+			value, err = nil, perr
+		}
+	}()
+	return queued.job(task.ctx)
+}
+
+// deliver queues a job's completion for the UI goroutine and wakes it
+func (q *WorkQueue) deliver(queued queuedJob, value any, err error) {
+	queued.task.cancel()
+	complete := func() {
+		q.pending.Add(-1)
+		queued.task.done.Store(true)
+		if queued.complete != nil {
+			queued.complete(value, err)
+		}
+	}
+	select {
+	case q.results <- complete:
+	case <-q.ctx.Done():
+		// Nobody drains a stopped queue's results; drop them if the buffer is full
+		select {
+		case q.results <- complete:
+		default:
+			return
+		}
+	}
+	if q.onResult != nil {
+		q.onResult()
+	}
+}
+
+// SubmitFunc queues a job with a typed result. complete is called on the UI goroutine.
+func SubmitFunc[T any](q *WorkQueue, job func(ctx context.Context) (T, error), complete func(value T, err error)) *Task {
+	return q.Submit(
+		func(ctx context.Context) (any, error) { return job(ctx) },
+		func(value any, err error) {
+			if complete == nil {
+				return
+			}
+			v, _ := value.(T)
+			complete(v, err)
+		},
+	)
+}
+
+// Theme work queue methods
+
+// WorkQueue returns the theme's work queue, starting it on first use. It is bound to the
+// theme's context, drained by BeginFrame, and wakes the window a theme was attached to
+// with NewWindow whenever a result is ready.
+func (t *Theme) WorkQueue() *WorkQueue {
+	t.workOnce.Do(func() {
+		t.work.Store(NewWorkQueue(t.ctx, 0, t.requestFrame))
+	})
+	return t.work.Load()
+}
+
+// Submit queues a job on the theme's work queue; complete runs on the UI goroutine
+func (t *Theme) Submit(job Job, complete func(value any, err error)) *Task {
+	return t.WorkQueue().Submit(job, complete)
+}
+
+// drainWork runs the completions of finished background jobs, if the queue was started
+func (t *Theme) drainWork() {
+	if q := t.work.Load(); q != nil {
+		q.Drain()
+	}
+}
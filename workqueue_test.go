@@ -0,0 +1,131 @@
+package fromage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitDrain drains q until want completions have run or the deadline passes
+func waitDrain(t *testing.T, q *WorkQueue, ready <-chan struct{}, want int) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for n := 0; n < want; {
+		select {
+		case <-ready:
+			n += q.Drain()
+		case <-deadline:
+			t.Fatalf("Timed out after %d of %d results", n, want)
+		}
+	}
+}
+
+func TestWorkQueueDeliversOnDrain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready := make(chan struct{}, 16)
+	q := NewWorkQueue(ctx, 2, func() { ready <- struct{}{} })
+
+	var got []int
+	for i := 1; i <= 3; i++ {
+		SubmitFunc(q, func(context.Context) (int, error) { return i * 10, nil }, func(v int, err error) {
+			if err != nil {
+				t.Errorf("Unexpected error %v", err)
+			}
+			got = append(got, v)
+		})
+	}
+	if q.Pending() != 3 {
+		t.Errorf("Expected 3 pending jobs, got %d", q.Pending())
+	}
+	// Completions only run when drained
+	waitDrain(t, q, ready, 3)
+	if len(got) != 3 || got[0]+got[1]+got[2] != 60 {
+		t.Errorf("Unexpected results %v", got)
+	}
+	if q.Pending() != 0 {
+		t.Errorf("Expected no pending jobs, got %d", q.Pending())
+	}
+}
+
+func TestWorkQueueRecoversPanics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ready := make(chan struct{}, 4)
+	q := NewWorkQueue(ctx, 1, func() { ready <- struct{}{} })
+
+	var err error
+	task := q.Submit(func(context.Context) (any, error) { panic("boom") }, func(_ any, e error) { err = e })
+	waitDrain(t, q, ready, 1)
+	var perr *PanicError
+	if !errors.As(err, &perr) || perr.Value != "boom" || len(perr.Stack) == 0 {
+		t.Errorf("Expected a PanicError, got %v", err)
+	}
+	if !task.Done() {
+		t.Error("Expected the task to be done")
+	}
+	// The worker survives the panic
+	var v any
+	q.Submit(func(context.Context) (any, error) { return "ok", nil }, func(value any, _ error) { v = value })
+	waitDrain(t, q, ready, 1)
+	if v != "ok" {
+		t.Errorf("Expected the worker to keep running, got %v", v)
+	}
+}
+
+func TestWorkQueueCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{}, 8)
+	q := NewWorkQueue(ctx, 1, func() { ready <- struct{}{} })
+
+	// Block the only worker until the queue is cancelled
+	started := make(chan struct{})
+	var blockedErr, queuedErr error
+	q.Submit(func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, func(_ any, err error) { blockedErr = err })
+	<-started
+	skipped := q.Submit(func(context.Context) (any, error) {
+		t.Error("Expected a cancelled job not to run")
+		return nil, nil
+	}, func(_ any, err error) { queuedErr = err })
+	skipped.Cancel()
+	cancel()
+	q.Wait()
+	q.Drain()
+	if !errors.Is(blockedErr, context.Canceled) || !errors.Is(queuedErr, context.Canceled) {
+		t.Errorf("Expected cancellation errors, got %v and %v", blockedErr, queuedErr)
+	}
+
+	var lateErr error
+	q.Submit(func(context.Context) (any, error) { return nil, nil }, func(_ any, err error) { lateErr = err })
+	q.Drain()
+	if !errors.Is(lateErr, context.Canceled) {
+		t.Errorf("Expected a job submitted after cancellation to fail, got %v", lateErr)
+	}
+}
+
+func TestThemeWorkQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	th := &Theme{ctx: ctx}
+	ready := make(chan struct{}, 4)
+	th.invalidate.Store(func() { ready <- struct{}{} })
+	if th.WorkQueue() != th.WorkQueue() {
+		t.Fatal("Expected a single work queue per theme")
+	}
+	var got any
+	th.Submit(func(context.Context) (any, error) { return 42, nil }, func(v any, _ error) { got = v })
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the window to be invalidated")
+	}
+	th.drainWork()
+	if got != 42 {
+		t.Errorf("Expected BeginFrame's drain to deliver the result, got %v", got)
+	}
+}